@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/getlantern/systray"
+	"github.com/skryvvara/focusframe/config"
+	"github.com/skryvvara/focusframe/internal/actions"
+	"github.com/skryvvara/focusframe/internal/gui"
+)
+
+// registerActions registers the actions concerning the user interface of the app, the others are registered by the
+// actions package.
+func registerActions() {
+	actions.Register(actions.Action{
+		Name:        "show-gui",
+		Description: "Open the window for managing applications",
+		Run: func(args actions.Args) error {
+			go gui.ShowGUI()
+			return nil
+		},
+	})
+	actions.Register(actions.Action{
+		Name:        "show-config",
+		Description: "Reveal the configuration file in the file explorer",
+		Run: func(args actions.Args) error {
+			return config.OpenConfigPath()
+		},
+	})
+	actions.Register(actions.Action{
+		Name:        "reload-config",
+		Description: "Read the configuration file again",
+		Run: func(args actions.Args) error {
+			config.Initialize()
+			bindHotkeys()
+			showWorkspaces()
+			return nil
+		},
+	})
+	actions.Register(actions.Action{
+		Name:        "quit",
+		Description: "Restore all windows and quit FocusFrame",
+		Run: func(args actions.Args) error {
+			systray.Quit()
+			return nil
+		},
+	})
+}
+
+// trayAction runs the invocation whenever the tray item is clicked.
+func trayAction(item *systray.MenuItem, invocation string) {
+	go func() {
+		for range item.ClickedCh {
+			actions.Run("tray", invocation)
+		}
+	}()
+}
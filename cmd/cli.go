@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/skryvvara/focusframe/config"
+	"github.com/skryvvara/focusframe/internal/actions"
+	"github.com/skryvvara/focusframe/internal/ipc"
+)
+
+// runCLI handles the command line arguments of a FocusFrame started to control the running instance.
+//
+// Returns the exit code.
+func runCLI(args []string) int {
+	switch args[0] {
+	case "run":
+		if len(args) < 2 {
+			break
+		}
+		invocation := actions.Invocation{Name: args[1], Args: args[2:]}.String()
+		// the name can contain the first argument, e.g. launch:Game.exe
+		if err := actions.Check(invocation); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if err := ipc.Send(config.Dir(), "cli", invocation); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	case "actions":
+		for _, action := range actions.List() {
+			fmt.Printf("%-40s %s\n", action.Usage(), action.Description)
+		}
+		return 0
+	}
+
+	fmt.Fprintln(os.Stderr, strings.TrimSpace(`
+Usage:
+  focusframe                        start FocusFrame
+  focusframe run <action> [args]    run an action in the running instance, e.g. "run apply Game.exe"
+  focusframe actions                list all actions`))
+	return 2
+}
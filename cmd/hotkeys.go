@@ -0,0 +1,48 @@
+package main
+
+import (
+	"log"
+	"maps"
+	"slices"
+
+	"github.com/skryvvara/focusframe/config"
+	"github.com/skryvvara/focusframe/input"
+	"github.com/skryvvara/focusframe/internal/actions"
+)
+
+// hotkeys is the registry of the hotkeys of the config, backed by system hotkeys.
+var hotkeys = input.NewHotkeys(input.NewSystemSource())
+
+// bindHotkeys replaces all hotkeys with the ones of the config, which maps them to action invocations like
+// "launch:Game.exe". Hotkeys that can't be bound are logged and skipped.
+func bindHotkeys() {
+	hotkeys.UnbindAll()
+
+	bindings := config.Config.Hotkeys
+	if len(bindings) == 0 {
+		// configs from before the [hotkeys] table only have a single key code, which doesn't need to have a name
+		bindHotkey(input.Combo{Key: config.Config.Global.Hotkey}, "toggle-manage")
+		return
+	}
+
+	// bind in a fixed order, so the same hotkey is reported every time two of them conflict
+	for _, s := range slices.Sorted(maps.Keys(bindings)) {
+		combo, err := input.ParseCombo(s)
+		if err != nil {
+			log.Println("Invalid hotkey:", err)
+			continue
+		}
+		bindHotkey(combo, bindings[s])
+	}
+}
+
+// bindHotkey binds the combo to the action invocation, errors are logged.
+func bindHotkey(combo input.Combo, invocation string) {
+	if err := actions.Check(invocation); err != nil {
+		log.Printf("Invalid action for hotkey %s: %v\n", combo, err)
+		return
+	}
+	if err := hotkeys.Bind(combo, invocation, func() { actions.Run("hotkey", invocation) }); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+)
+
+// pausedIcon returns the tray icon with a pause badge in its lower right corner. It is derived from the PNG image of
+// the icon file, which holds the largest size, and returned as an icon file with just that image.
+//
+// Returns an error if the icon file has no PNG image.
+func pausedIcon(ico []byte) ([]byte, error) {
+	img, err := pngOfIcon(ico)
+	if err != nil {
+		return nil, err
+	}
+
+	badged := image.NewNRGBA(img.Bounds())
+	draw.Draw(badged, badged.Bounds(), img, img.Bounds().Min, draw.Src)
+	drawPauseBadge(badged)
+
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, badged); err != nil {
+		return nil, err
+	}
+	return iconOfPNG(encoded.Bytes(), badged.Bounds().Dx(), badged.Bounds().Dy()), nil
+}
+
+// pngOfIcon decodes the first PNG image of an icon file.
+func pngOfIcon(ico []byte) (image.Image, error) {
+	if len(ico) < 6 {
+		return nil, errors.New("invalid icon file")
+	}
+	count := int(binary.LittleEndian.Uint16(ico[4:]))
+	for i := 0; i < count; i++ {
+		entry := 6 + i*16
+		if len(ico) < entry+16 {
+			break
+		}
+		size := int(binary.LittleEndian.Uint32(ico[entry+8:]))
+		offset := int(binary.LittleEndian.Uint32(ico[entry+12:]))
+		if offset+size > len(ico) || !bytes.HasPrefix(ico[offset:offset+size], []byte("\x89PNG")) {
+			continue
+		}
+		return png.Decode(bytes.NewReader(ico[offset : offset+size]))
+	}
+	return nil, errors.New("icon file has no PNG image")
+}
+
+// iconOfPNG returns an icon file with the PNG image as its only entry.
+func iconOfPNG(data []byte, width int, height int) []byte {
+	var ico bytes.Buffer
+	binary.Write(&ico, binary.LittleEndian, [3]uint16{0, 1, 1}) // reserved, type icon, one image
+	// a size of 0 stands for 256 pixels
+	ico.Write([]byte{byte(width % 256), byte(height % 256), 0, 0})
+	binary.Write(&ico, binary.LittleEndian, [2]uint16{1, 32}) // color planes, bits per pixel
+	binary.Write(&ico, binary.LittleEndian, [2]uint32{uint32(len(data)), 22})
+	ico.Write(data)
+	return ico.Bytes()
+}
+
+// drawPauseBadge draws a pause symbol, two bars on an amber disc, over the lower right quarter of the image.
+func drawPauseBadge(img *image.NRGBA) {
+	b := img.Bounds()
+	radius := b.Dx() / 4
+	cx, cy := b.Max.X-radius-1, b.Max.Y-radius-1
+
+	amber := color.NRGBA{R: 0xf5, G: 0xa6, B: 0x23, A: 0xff}
+	white := color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	barWidth, barHeight, gap := radius/3, radius, radius/4
+
+	for y := cy - radius; y <= cy+radius; y++ {
+		for x := cx - radius; x <= cx+radius; x++ {
+			dx, dy := x-cx, y-cy
+			if dx*dx+dy*dy > radius*radius {
+				continue
+			}
+			c := amber
+			inBar := (dx >= -gap-barWidth && dx < -gap || dx >= gap && dx < gap+barWidth) &&
+				dy >= -barHeight/2 && dy < barHeight/2
+			if inBar {
+				c = white
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+}
@@ -1,27 +1,49 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/getlantern/systray"
 	"github.com/skryvvara/focusframe/config"
+	"github.com/skryvvara/focusframe/internal/actions"
 	"github.com/skryvvara/focusframe/internal/browser"
 	"github.com/skryvvara/focusframe/internal/gui"
+	"github.com/skryvvara/focusframe/internal/ipc"
 	"github.com/skryvvara/focusframe/internal/startup"
+	"github.com/skryvvara/focusframe/process"
 	"github.com/skryvvara/focusframe/window"
 )
 
 //go:embed monitor.ico
 var iconFS embed.FS
 
+// restartElevated starts FocusFrame again as administrator once this instance restored all windows and exited.
+var restartElevated bool
+
 func main() {
+	registerActions()
+	if len(os.Args) > 1 {
+		os.Exit(runCLI(os.Args[1:]))
+	}
+
 	config.Initialize()
 
+	// windows a previous run didn't restore because it crashed or was killed
+	unrestored, err := window.LoadJournal(filepath.Join(config.Dir(), "journal.json"))
+	if err != nil {
+		log.Println("Error loading journal:", err)
+	}
+
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
 
@@ -30,29 +52,98 @@ func main() {
 		systray.Quit()
 	}()
 
-	go window.AddAppOnKeyPress(config.Config.Global.Hotkey)
+	bindHotkeys()
+	go hotkeys.Run(context.Background())
+	go func() {
+		err := ipc.Serve(context.Background(), config.Dir(), func(source string, invocation string) error {
+			return actions.Run(source, invocation)
+		})
+		if err != nil {
+			log.Println("Error serving IPC requests:", err)
+		}
+	}()
 	go window.WatchForegroundWindowChange()
+	go window.WatchProcesses(context.Background(), process.NewWatcher(0))
 
-	systray.Run(onReady, onExit)
+	systray.Run(func() { onReady(unrestored) }, onExit)
 }
 
-// onReady setup systray
-func onReady() {
+// onReady setup systray, unrestored are the windows changed by a previous run that can still be restored
+func onReady(unrestored []window.OriginalState) {
 	iconData, err := iconFS.ReadFile("monitor.ico")
 	if err != nil {
 		log.Fatal("Error reading icon: ", err)
 	}
 
+	pausedIconData, err := pausedIcon(iconData)
+	if err != nil {
+		log.Println("Error creating paused icon:", err)
+		pausedIconData = iconData
+	}
+
 	systray.SetIcon(iconData)
 	systray.SetTitle("FocusFrame")
 	systray.SetTooltip(fmt.Sprintf("FocusFrame Version: %s", config.Version))
 
-	mManageApplications := systray.AddMenuItem("Manage Applications", "Manage Applications")
+	trayAction(systray.AddMenuItem("Manage Applications", "Manage Applications"), "show-gui")
+	trayAction(systray.AddMenuItem("Restore All Windows", "Restore the original style and size of all windows"), "restore")
+	mRestoreUnrestored := systray.AddMenuItem(
+		fmt.Sprintf("Restore %d Windows from Last Session", len(unrestored)),
+		"Restore the windows FocusFrame changed before it was closed unexpectedly",
+	)
+	if len(unrestored) == 0 {
+		mRestoreUnrestored.Hide()
+	}
+	mProblems := systray.AddMenuItem("", "")
+	mProblems.Hide()
+	window.OnProblemsChanged = func(problems []window.Problem) {
+		showProblems(mProblems, problems)
+	}
+
+	mPause := systray.AddMenuItem("Pause", "Stop applying settings to windows until resumed")
+	trayAction(mPause, "toggle-pause")
+	mPauseFor := systray.AddMenuItem("Pause For", "Stop applying settings to windows for a while")
+	trayAction(mPauseFor.AddSubMenuItem("10 Minutes", "Resume after 10 minutes"), "pause 10m")
+	trayAction(mPauseFor.AddSubMenuItem("1 Hour", "Resume after an hour"), "pause 1h")
+	trayAction(
+		mPauseFor.AddSubMenuItem("Until App Loses Focus", "Resume when the last focused managed app loses focus"),
+		"pause-until-blur",
+	)
+	window.OnPauseChanged = func(status window.PauseStatus) {
+		showPause(mPause, status, iconData, pausedIconData)
+	}
+
+	workspaceMenu.Lock()
+	workspaceMenu.parent = systray.AddMenuItem("Workspaces", "Arrange several apps at once")
+	workspaceMenu.items = make(map[string]*systray.MenuItem)
+	workspaceMenu.Unlock()
+	showWorkspaces()
+	window.OnWorkspaceChanged = func(active string) {
+		workspaceMenu.Lock()
+		workspaceMenu.active = active
+		workspaceMenu.Unlock()
+		showWorkspaces()
+	}
+
+	// the app whose layout the tray shows, OnLayoutChanged sets it on another goroutine
+	var layoutApp struct {
+		sync.Mutex
+		executable string
+	}
+	mLayout := systray.AddMenuItem("", "Switch the app to its next layout")
+	mLayout.Hide()
+	window.OnLayoutChanged = func(executable string, layout string) {
+		layoutApp.Lock()
+		layoutApp.executable = executable
+		layoutApp.Unlock()
+		mLayout.SetTitle(fmt.Sprintf("Layout of %s: %s", executable, layout))
+		mLayout.Show()
+	}
 
 	systray.AddSeparator()
 
-	mShowConfig := systray.AddMenuItem("Show Configuration", "Show Configuration")
-	mReloadConfig := systray.AddMenuItem("Reload Configuration", "Reload Configuration")
+	trayAction(systray.AddMenuItem("Show Configuration", "Show Configuration"), "show-config")
+	trayAction(systray.AddMenuItem("Reload Configuration", "Reload Configuration"), "reload-config")
 	mWiki := systray.AddMenuItem("Open Wiki", "Open Wiki")
 	mForum := systray.AddMenuItem("Open Forum", "Open Forum")
 	mGithub := systray.AddMenuItem("Open Github", "Open Github repository")
@@ -68,18 +159,21 @@ func onReady() {
 
 	systray.AddSeparator()
 
-	mQuit := systray.AddMenuItem("Quit", "Quit the whole app")
+	trayAction(systray.AddMenuItem("Quit", "Quit the whole app"), "quit")
 
 	for {
 		select {
-		case <-mManageApplications.ClickedCh:
-			go gui.ShowGUI()
-		case <-mShowConfig.ClickedCh:
-			if err := config.OpenConfigPath(); err != nil {
-				log.Println(err)
-			}
-		case <-mReloadConfig.ClickedCh:
-			config.Initialize()
+		case <-mRestoreUnrestored.ClickedCh:
+			mRestoreUnrestored.Hide()
+			go window.RestoreWindows(unrestored)
+		case <-mLayout.ClickedCh:
+			layoutApp.Lock()
+			invocation := actions.Invocation{Name: "cycle-layout", Args: []string{layoutApp.executable}}
+			layoutApp.Unlock()
+			go actions.Run("tray", invocation.String())
+		case <-mProblems.ClickedCh:
+			restartElevated = true
+			systray.Quit()
 		case <-mWiki.ClickedCh:
 			if err := browser.OpenURL(gui.REPO_URL + "/wiki"); err != nil {
 				log.Println(err)
@@ -108,14 +202,106 @@ func onReady() {
 					mRunOnStartup.Check()
 				}
 			}
-		case <-mQuit.ClickedCh:
-			fmt.Println("Requesting Exit")
-			systray.Quit()
 		}
 	}
 }
 
-// onExit use for cleanup later, currently has no use
+// workspaceMenu is the tray submenu listing the workspaces of the config. Menu items can't be removed, so the items of
+// workspaces that were removed from the config are hidden instead.
+var workspaceMenu struct {
+	sync.Mutex
+	parent *systray.MenuItem
+	items  map[string]*systray.MenuItem
+	active string
+}
+
+// showWorkspaces brings the workspace submenu up to date with the workspaces of the config and checks the active one.
+// Workspaces added by a reload are appended to the end of the menu.
+func showWorkspaces() {
+	workspaceMenu.Lock()
+	defer workspaceMenu.Unlock()
+	if workspaceMenu.parent == nil {
+		// the tray is not set up yet
+		return
+	}
+
+	names := config.WorkspaceNames()
+	for _, name := range names {
+		if _, ok := workspaceMenu.items[name]; ok {
+			continue
+		}
+		item := workspaceMenu.parent.AddSubMenuItem(name, fmt.Sprintf("Arrange the apps of %s, click again to restore the arrangement from before", name))
+		trayAction(item, actions.Invocation{Name: "toggle-workspace", Args: []string{name}}.String())
+		workspaceMenu.items[name] = item
+	}
+
+	for name, item := range workspaceMenu.items {
+		if slices.Contains(names, name) {
+			item.Show()
+		} else {
+			item.Hide()
+		}
+		if name == workspaceMenu.active {
+			item.Check()
+		} else {
+			item.Uncheck()
+		}
+	}
+	if len(names) == 0 {
+		workspaceMenu.parent.Hide()
+	} else {
+		workspaceMenu.parent.Show()
+	}
+}
+
+// showProblems shows the managed apps FocusFrame can't change in the tray. Clicking the item restarts FocusFrame as
+// administrator, which is what resolves most of them, so it is disabled if FocusFrame already is.
+func showProblems(item *systray.MenuItem, problems []window.Problem) {
+	if len(problems) == 0 {
+		item.Hide()
+		return
+	}
+
+	advice := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		log.Println(problem.Advice())
+		advice = append(advice, problem.Advice())
+	}
+	if len(problems) == 1 {
+		item.SetTitle(fmt.Sprintf("Can't Manage %s, Restart as Administrator", problems[0].Executable))
+	} else {
+		item.SetTitle(fmt.Sprintf("Can't Manage %d Apps, Restart as Administrator", len(problems)))
+	}
+	item.SetTooltip(strings.Join(advice, "\n"))
+	if process.Elevated() {
+		item.Disable()
+	}
+	item.Show()
+}
+
+// showPause reflects whether window management is paused in the tray icon, its tooltip and the pause item.
+func showPause(item *systray.MenuItem, status window.PauseStatus, icon []byte, pausedIcon []byte) {
+	tooltip := fmt.Sprintf("FocusFrame Version: %s", config.Version)
+	if !status.Paused {
+		systray.SetIcon(icon)
+		systray.SetTooltip(tooltip)
+		item.SetTitle("Pause")
+		item.SetTooltip("Stop applying settings to windows until resumed")
+		return
+	}
+	systray.SetIcon(pausedIcon)
+	systray.SetTooltip(tooltip + "\n" + status.String())
+	item.SetTitle("Resume")
+	item.SetTooltip("Continue applying settings to windows")
+}
+
+// onExit restores all windows FocusFrame changed.
 func onExit() {
-	// clean up here
+	window.RestoreAll()
+
+	if restartElevated {
+		if err := startup.RestartElevated(); err != nil {
+			log.Println("Error restarting as administrator:", err)
+		}
+	}
 }
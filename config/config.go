@@ -1,55 +1,295 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/creasty/defaults"
+	"github.com/skryvvara/focusframe/zones"
 )
 
 var Version string
 
+const (
+	// DefaultReadyStableFor is how long a window's rect and style must stay unchanged before it is considered ready.
+	DefaultReadyStableFor = 200 * time.Millisecond
+	// DefaultReadyTimeout is how long FocusFrame waits at most for a window to become ready.
+	DefaultReadyTimeout = 10 * time.Second
+
+	// DefaultEnforceMaxAttempts is how often a window is re-applied within DefaultEnforceWindow before giving up.
+	DefaultEnforceMaxAttempts = 5
+	// DefaultEnforceBackoff is the delay before the first re-apply, it is doubled for every further attempt.
+	DefaultEnforceBackoff = 250 * time.Millisecond
+	// DefaultEnforceWindow is the period in which re-applies are counted for loop detection.
+	DefaultEnforceWindow = time.Minute
+)
+
+// Duration is a time.Duration which is written to the config file as a duration string like "750ms". Numbers are
+// always seconds, in the config file as well as in JSON, which is how delay was stored before it became a Duration.
+type Duration time.Duration
+
+const (
+	// SizeModeFrame applies the dimensions to the visible frame of a window, this is the default.
+	SizeModeFrame = "frame"
+	// SizeModeClient applies the dimensions to the client area of a window, so its caption and borders lie outside.
+	SizeModeClient = "client"
+)
+
 type WindowSettings struct {
-	Width   int `toml:"width"`
-	Height  int `toml:"height"`
-	OffsetX int `toml:"offsetX"`
-	OffsetY int `toml:"offsetY"`
-	Delay   int `toml:"delay"`
+	Width    int      `toml:"width"`
+	Height   int      `toml:"height"`
+	OffsetX  int      `toml:"offsetX"`
+	OffsetY  int      `toml:"offsetY"`
+	Delay    Duration `toml:"delay"`
+	SizeMode string   `toml:"size_mode,omitempty"` // SizeModeFrame or SizeModeClient
+}
+
+// ReadySettings configure how FocusFrame detects that a window has finished initializing and can be moved.
+type ReadySettings struct {
+	StableFor Duration `toml:"stable_for,omitempty"` // Rect and style must stay unchanged for this long
+	Title     string   `toml:"title,omitempty"`      // Regular expression the window title has to match
+	Timeout   Duration `toml:"timeout,omitempty"`    // Upper limit for waiting on the window
+}
+
+// EnforceSettings configure whether FocusFrame keeps a window at its configured geometry when the app moves or resizes
+// it on its own after the settings were applied.
+type EnforceSettings struct {
+	Enabled     bool     `toml:"enabled"`
+	Lock        bool     `toml:"lock,omitempty"`         // Also undo moving or resizing the window by the user
+	MaxAttempts int      `toml:"max_attempts,omitempty"` // Re-applies within Window before giving up
+	Backoff     Duration `toml:"backoff,omitempty"`      // Delay of the first re-apply, doubled for every further one
+	Window      Duration `toml:"window,omitempty"`       // Period in which re-applies are counted
+}
+
+// StyleSettings configure how FocusFrame changes the style of a window. Flags are given by their name, e.g.
+// "WS_CAPTION" for styles or "WS_EX_CLIENTEDGE" for extended styles.
+type StyleSettings struct {
+	Decorations  bool     `toml:"decorations,omitempty"`   // Keep the title bar and borders of the window
+	Add          []string `toml:"add,omitempty"`           // Styles to add
+	Remove       []string `toml:"remove,omitempty"`        // Styles to remove
+	AddEx        []string `toml:"add_ex,omitempty"`        // Extended styles to add
+	RemoveEx     []string `toml:"remove_ex,omitempty"`     // Extended styles to remove
+	FrameChanged bool     `toml:"frame_changed,omitempty"` // Notify the window that its frame changed
+}
+
+// Triggers for applying the settings of a managed app, see ManagedApp.ApplyOn.
+const (
+	ApplyOnShown  = "shown"  // A window of the app was created or became visible
+	ApplyOnFocus  = "focus"  // A window of the app received focus
+	ApplyOnTitle  = "title"  // The title of the main window of the app changed
+	ApplyOnManual = "manual" // Only when requested, e.g. by adding the app
+	ApplyOnOnce   = "once"   // Only once per process, on the first window shown or focused unless other triggers are given
+)
+
+// DefaultApplyOn are the triggers of apps without apply_on.
+var DefaultApplyOn = []string{ApplyOnFocus}
+
+const (
+	// FocusModeBackdrop covers the rest of the monitor with a solid color behind the app.
+	FocusModeBackdrop = "backdrop"
+	// FocusModeMinimize minimizes the other windows on the monitor of the app.
+	FocusModeMinimize = "minimize"
+)
+
+// FocusSettings configure hiding everything else on the monitor of a managed app while it has focus. The other windows
+// come back when it loses focus.
+type FocusSettings struct {
+	Mode  string `toml:"mode,omitempty"`  // FocusModeBackdrop or FocusModeMinimize, off if empty
+	Color string `toml:"color,omitempty"` // Color of the backdrop like "#202020", black if empty
+}
+
+// GuardFocusSettings configure keeping new windows of other apps from taking focus from a managed app while it has
+// focus. Such windows are put behind the app, which gets focus back.
+type GuardFocusSettings struct {
+	Enabled bool     `toml:"enabled,omitempty"`
+	Allow   []string `toml:"allow,omitempty"` // Executables whose windows may take focus, e.g. a voice chat
+}
+
+// StateSettings configure how FocusFrame handles windows that are maximized or in exclusive fullscreen.
+type StateSettings struct {
+	Unmaximize     bool   `toml:"unmaximize,omitempty"`      // Restore maximized windows first, otherwise they are skipped
+	FullscreenKeys string `toml:"fullscreen_keys,omitempty"` // Keys that make the app leave fullscreen, e.g. "Alt+Enter"
+}
+
+// DefaultLayout is the name of the layout made of the dimensions of a managed app, see ManagedApp.Layouts.
+const DefaultLayout = "default"
+
+// Layout is a named variant of the dimensions of a managed app, e.g. "21:9 full width".
+type Layout struct {
+	Name       string         `toml:"name"`
+	Dimensions WindowSettings `toml:"dimensions"`
 }
 
 type ManagedApp struct {
-	Executable   string         `toml:"executable"`
-	FriendlyName string         `toml:"friendly_name"`
-	Dimensions   WindowSettings `toml:"dimensions"`
+	Executable   string             `toml:"executable"`
+	FriendlyName string             `toml:"friendly_name"`
+	Dimensions   WindowSettings     `toml:"dimensions"`
+	Ready        ReadySettings      `toml:"ready,omitempty"`
+	Enforce      EnforceSettings    `toml:"enforce,omitempty"`
+	Style        StyleSettings      `toml:"style,omitempty"`
+	State        StateSettings      `toml:"state,omitempty"`
+	ApplyOn      []string           `toml:"apply_on,omitempty"`      // When the settings are applied, defaults to DefaultApplyOn
+	Launch       string             `toml:"launch,omitempty"`        // Path of the executable that starts the app, e.g. its launcher
+	Layouts      []Layout           `toml:"layouts,omitempty"`       // Variants of Dimensions, which is the DefaultLayout
+	ActiveLayout string             `toml:"active_layout,omitempty"` // Name of the layout in use, DefaultLayout if empty
+	Companions   []Companion        `toml:"companions,omitempty"`    // Apps kept beside the app while it has focus
+	Focus        FocusSettings      `toml:"focus,omitempty"`
+	GuardFocus   GuardFocusSettings `toml:"guard_focus,omitempty"`
+}
+
+// layoutIndex returns the index of the layout with the given name in Layouts, -1 for the DefaultLayout or a layout
+// that doesn't exist.
+func (app ManagedApp) layoutIndex(name string) int {
+	for i, layout := range app.Layouts {
+		if layout.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+const (
+	CornerTopLeft     = "top-left"
+	CornerTopRight    = "top-right"
+	CornerBottomLeft  = "bottom-left"
+	CornerBottomRight = "bottom-right"
+
+	// DefaultCompanionWidth and DefaultCompanionHeight are the size of a companion window without a configured one.
+	DefaultCompanionWidth  = 480
+	DefaultCompanionHeight = 270
+)
+
+// Companion is an app, e.g. a video or chat, whose window is shrunk into a corner of the free area beside a managed
+// app and kept above other windows while the managed app has focus.
+type Companion struct {
+	Executable string `toml:"executable"`
+	// Corner is the corner the window is placed in. The left corners are those of the area to the left of the managed
+	// app, the right ones those of the area to its right. Defaults to CornerBottomRight.
+	Corner string `toml:"corner,omitempty"`
+	Width  int    `toml:"width,omitempty"`  // Size of the visible frame, shrunk to fit the free area
+	Height int    `toml:"height,omitempty"` // Size of the visible frame, shrunk to fit the free area
+	Margin int    `toml:"margin,omitempty"` // Distance to the edges of the free area
+}
+
+// WorkspaceApp places a managed app as part of a workspace.
+type WorkspaceApp struct {
+	Executable string `toml:"executable"`
+	Layout     string `toml:"layout,omitempty"`  // Layout of the app, DefaultLayout if empty
+	Monitor    int    `toml:"monitor,omitempty"` // Monitor the layout is moved to, counted from the left starting at 1
+}
+
+// Workspace is a set of managed apps that are arranged together, e.g. a game next to the apps used for streaming it.
+type Workspace struct {
+	Apps   []WorkspaceApp `toml:"apps"`
+	Launch bool           `toml:"launch,omitempty"` // Start apps that are not running with their launch setting
+}
+
+// ZoneRect is a zone of a canvas zone layout.
+type ZoneRect struct {
+	X      int `toml:"x"`
+	Y      int `toml:"y"`
+	Width  int `toml:"width"`
+	Height int `toml:"height"`
+}
+
+// ZoneLayout divides the work area of a monitor into zones windows can be sent to. The fields apply to the types of
+// layout as described in the zones package.
+type ZoneLayout struct {
+	Monitor         int        `toml:"monitor,omitempty"` // Counted from the left starting at 1, 0 for all other monitors
+	Type            string     `toml:"type"`              // grid, columns, rows, centered or canvas
+	Rows            int        `toml:"rows,omitempty"`
+	Columns         int        `toml:"columns,omitempty"`
+	RowHeights      []int      `toml:"row_heights,omitempty"`   // Relative heights of the rows, instead of rows
+	ColumnWidths    []int      `toml:"column_widths,omitempty"` // Relative widths of the columns, instead of columns
+	Cells           [][]int    `toml:"cells,omitempty"`         // Zone of every cell of a grid, to merge cells
+	Center          int        `toml:"center,omitempty"`        // Width of the middle zone of centered in percent
+	Canvas          []ZoneRect `toml:"canvas,omitempty"`        // Zones of a canvas relative to the reference size
+	ReferenceWidth  int        `toml:"reference_width,omitempty"`
+	ReferenceHeight int        `toml:"reference_height,omitempty"`
+	Spacing         int        `toml:"spacing,omitempty"` // Gap between the zones in pixels
+}
+
+// Layout returns the zone layout in the form the zones package works with.
+func (z ZoneLayout) Layout() zones.Layout {
+	layout := zones.Layout{
+		Kind:            zones.Kind(z.Type),
+		Rows:            z.RowHeights,
+		Columns:         z.ColumnWidths,
+		RowCount:        z.Rows,
+		ColumnCount:     z.Columns,
+		Cells:           z.Cells,
+		Center:          z.Center,
+		ReferenceWidth:  z.ReferenceWidth,
+		ReferenceHeight: z.ReferenceHeight,
+		Spacing:         z.Spacing,
+	}
+	for _, r := range z.Canvas {
+		layout.Zones = append(layout.Zones, zones.Rect{Left: r.X, Top: r.Y, Right: r.X + r.Width, Bottom: r.Y + r.Height})
+	}
+	return layout
+}
+
+// validationArea is the work area zone layouts are resolved in to validate them, the actual monitors may differ.
+var validationArea = zones.Rect{Right: 1920, Bottom: 1080}
+
+// Validate checks that the zone layout has zones and can be resolved.
+//
+// Returns an error describing what is wrong with the layout.
+func (z ZoneLayout) Validate() error {
+	if z.Monitor < 0 {
+		return fmt.Errorf("monitors are counted from 1, got %d", z.Monitor)
+	}
+	_, err := z.Layout().Resolve(validationArea)
+	return err
 }
 
 type Type struct {
 	Global struct {
-		Width     int  `toml:"width" default:"1920"`
-		Height    int  `toml:"height" default:"1090"`
-		OffsetX   int  `toml:"offsetX" default:"0"`
-		OffsetY   int  `toml:"offsetY" default:"0"`
-		Delay     int  `toml:"delay" default:"0"`
-		Hotkey    int  `toml:"hotkey" default:"115"`
-		DarkTheme bool `toml:"dark_theme" default:"false"`
+		Width     int      `toml:"width" default:"1920"`
+		Height    int      `toml:"height" default:"1090"`
+		OffsetX   int      `toml:"offsetX" default:"0"`
+		OffsetY   int      `toml:"offsetY" default:"0"`
+		Delay     Duration `toml:"delay" default:"0"`
+		Hotkey    int      `toml:"hotkey" default:"115"`
+		DarkTheme bool     `toml:"dark_theme" default:"false"`
 	} `toml:"global"`
 	ManagedApps map[string]ManagedApp `toml:"managed_apps"`
+	// Hotkeys maps hotkeys like "Ctrl+Shift+F4" to the names of the actions they run. Without any, global.hotkey
+	// toggles whether the focused app is managed.
+	Hotkeys map[string]string `toml:"hotkeys,omitempty"`
+	// Workspaces are sets of managed apps by name which are arranged together.
+	Workspaces map[string]Workspace `toml:"workspaces,omitempty"`
+	// Zones are the zone layouts of the monitors.
+	Zones []ZoneLayout `toml:"zones,omitempty"`
 }
 
+// ManagedAppsLock guards changes of the config, Config.ManagedApps in particular. Read managed apps through
+// GetManagedApp and ManagedExecutables, which hold it.
 var ManagedAppsLock sync.Mutex
 var Config Type
 var configPath string
 
 // Initialize loads the configuration from file into the Config struct.
 func Initialize() {
+	ManagedAppsLock.Lock()
+	defer ManagedAppsLock.Unlock()
+
 	Config.ManagedApps = make(map[string]ManagedApp)
+	Config.Hotkeys = nil
+	Config.Workspaces = nil
+	Config.Zones = nil
 
 	configPath = getConfigPath()
 
@@ -82,6 +322,11 @@ func getConfigPath() string {
 	}
 }
 
+// Dir returns the directory containing the config file, which is also used for other files FocusFrame keeps.
+func Dir() string {
+	return filepath.Dir(getConfigPath())
+}
+
 // assertPath checks if the given path exists, alternatively it tries to create all missing directories of the path.
 //
 // returns nil if the path exists or could be created, returns an error if creation of the path failed.
@@ -156,6 +401,188 @@ func RemoveApplication(executable string) {
 	}
 }
 
+// GetManagedApp returns the settings of a managed application and whether the executable is managed. Unlike reading
+// Config.ManagedApps it is safe while the config is changed.
+func GetManagedApp(executable string) (ManagedApp, bool) {
+	ManagedAppsLock.Lock()
+	defer ManagedAppsLock.Unlock()
+	app, ok := Config.ManagedApps[executable]
+	return app, ok
+}
+
+// ManagedExecutables returns the executables of all managed applications in alphabetical order.
+func ManagedExecutables() []string {
+	ManagedAppsLock.Lock()
+	defer ManagedAppsLock.Unlock()
+	return slices.Sorted(maps.Keys(Config.ManagedApps))
+}
+
+// SetDimensions replaces the window settings of the active layout of the managed app and tries to write the changes to
+// the config file.
+//
+// Returns an error if the app is not managed, the settings are invalid or the config could not be saved.
+func SetDimensions(executable string, ws WindowSettings) error {
+	ManagedAppsLock.Lock()
+	defer ManagedAppsLock.Unlock()
+
+	app, ok := Config.ManagedApps[executable]
+	if !ok {
+		return fmt.Errorf("%s is not a managed app", executable)
+	}
+	if !ws.IsValid() {
+		return fmt.Errorf("invalid window settings for %s", executable)
+	}
+
+	if i := app.layoutIndex(app.ActiveLayout); i >= 0 {
+		app.Layouts = slices.Clone(app.Layouts)
+		app.Layouts[i].Dimensions = ws
+	} else {
+		app.Dimensions = ws
+	}
+	Config.ManagedApps[executable] = app
+	return SaveConfig()
+}
+
+// LayoutNames returns the names of the layouts of a managed app in the order they are cycled, starting with
+// DefaultLayout.
+func LayoutNames(executable string) []string {
+	app, _ := GetManagedApp(executable)
+	return app.layoutNames()
+}
+
+func (app ManagedApp) layoutNames() []string {
+	names := []string{DefaultLayout}
+	for _, layout := range app.Layouts {
+		names = append(names, layout.Name)
+	}
+	return names
+}
+
+// GetActiveLayout returns the name of the layout a managed app uses, DefaultLayout if its active layout doesn't exist.
+func GetActiveLayout(executable string) string {
+	app, _ := GetManagedApp(executable)
+	if app.layoutIndex(app.ActiveLayout) < 0 {
+		return DefaultLayout
+	}
+	return app.ActiveLayout
+}
+
+// NextLayout returns the name of the layout following the active one of a managed app, wrapping around after the
+// last one.
+func NextLayout(executable string) string {
+	names := LayoutNames(executable)
+	i := slices.Index(names, GetActiveLayout(executable))
+	return names[(i+1)%len(names)]
+}
+
+// GetLayoutSettings returns the WindowSettings of the layout with the given name of a managed app.
+//
+// Returns either the WindowSettings or an error if the app is not managed or has no valid layout with that name.
+func GetLayoutSettings(executable string, name string) (WindowSettings, error) {
+	app, ok := GetManagedApp(executable)
+	if !ok {
+		return WindowSettings{}, fmt.Errorf("%s is not a managed app", executable)
+	}
+	return layoutSettings(app, executable, name)
+}
+
+// layoutSettings is GetLayoutSettings for the settings of a managed app that were already read.
+func layoutSettings(app ManagedApp, executable string, name string) (WindowSettings, error) {
+	ws := app.Dimensions
+	if name != DefaultLayout && name != "" {
+		i := app.layoutIndex(name)
+		if i < 0 {
+			return WindowSettings{}, fmt.Errorf("%s has no layout %q, its layouts are %s", executable, name, strings.Join(app.layoutNames(), ", "))
+		}
+		ws = app.Layouts[i].Dimensions
+	}
+	if !ws.IsValid() {
+		return WindowSettings{}, fmt.Errorf("invalid window settings for layout %q of %s", name, executable)
+	}
+	return ws, nil
+}
+
+// GetWorkspace returns the workspace with the given name.
+func GetWorkspace(name string) (Workspace, bool) {
+	ManagedAppsLock.Lock()
+	defer ManagedAppsLock.Unlock()
+	workspace, ok := Config.Workspaces[name]
+	return workspace, ok
+}
+
+// WorkspaceNames returns the names of all workspaces in alphabetical order.
+func WorkspaceNames() []string {
+	ManagedAppsLock.Lock()
+	defer ManagedAppsLock.Unlock()
+	return slices.Sorted(maps.Keys(Config.Workspaces))
+}
+
+// GetZoneLayout returns the zone layout of the monitor with the given number, counted from the left starting at 1.
+// Monitors without their own layout use the one without a monitor.
+func GetZoneLayout(monitor int) (ZoneLayout, bool) {
+	ManagedAppsLock.Lock()
+	defer ManagedAppsLock.Unlock()
+	var fallback ZoneLayout
+	found := false
+	for _, z := range Config.Zones {
+		if z.Monitor == monitor {
+			return z, true
+		}
+		if z.Monitor == 0 && !found {
+			fallback, found = z, true
+		}
+	}
+	return fallback, found
+}
+
+// SetZoneLayouts adds the zone layouts to the config, replacing the ones of the same monitors, and tries to write the
+// changes to the config file.
+//
+// Returns an error if a layout is invalid, two layouts are for the same monitor or the config could not be saved.
+func SetZoneLayouts(layouts []ZoneLayout) error {
+	ManagedAppsLock.Lock()
+	defer ManagedAppsLock.Unlock()
+
+	monitors := map[int]bool{}
+	for _, z := range layouts {
+		if err := z.Validate(); err != nil {
+			return fmt.Errorf("invalid zone layout for monitor %d: %w", z.Monitor, err)
+		}
+		if monitors[z.Monitor] {
+			return fmt.Errorf("more than one zone layout for monitor %d", z.Monitor)
+		}
+		monitors[z.Monitor] = true
+	}
+
+	kept := slices.DeleteFunc(slices.Clone(Config.Zones), func(z ZoneLayout) bool { return monitors[z.Monitor] })
+	Config.Zones = append(kept, layouts...)
+	return SaveConfig()
+}
+
+// SetActiveLayout makes the layout with the given name the one the managed app uses and tries to write the changes to
+// the config file.
+//
+// Returns an error if the app is not managed, has no valid layout with that name or the config could not be saved.
+func SetActiveLayout(executable string, name string) error {
+	ManagedAppsLock.Lock()
+	defer ManagedAppsLock.Unlock()
+
+	app, ok := Config.ManagedApps[executable]
+	if !ok {
+		return fmt.Errorf("%s is not a managed app", executable)
+	}
+	if _, err := layoutSettings(app, executable, name); err != nil {
+		return err
+	}
+	if name == DefaultLayout {
+		name = ""
+	}
+
+	app.ActiveLayout = name
+	Config.ManagedApps[executable] = app
+	return SaveConfig()
+}
+
 // SaveConfig tries to write the current configuration to file and returns an error if it fails.
 func SaveConfig() error {
 	if len(configPath) <= 0 {
@@ -193,19 +620,63 @@ func (ws WindowSettings) IsValid() bool {
 		return false
 	}
 
+	if ws.SizeMode != "" && ws.SizeMode != SizeModeFrame && ws.SizeMode != SizeModeClient {
+		return false
+	}
+
 	// Additional checks can be added here as needed
 	return true
 }
 
-// GetWindowSettings returns the WindowSettings specific to a managed application or the global WindowSettings if no
-// specific or invalid settings were found.
+// GetWindowSettings returns the WindowSettings of the active layout of a managed application or the global
+// WindowSettings if no specific or invalid settings were found.
 func GetWindowSettings(executable string) WindowSettings {
-	if app, ok := Config.ManagedApps[executable]; ok {
+	if app, ok := GetManagedApp(executable); ok {
+		if i := app.layoutIndex(app.ActiveLayout); i >= 0 && app.Layouts[i].Dimensions.IsValid() {
+			return app.Layouts[i].Dimensions
+		}
 		return app.Dimensions
 	}
 	return getGlobalWindowSettings()
 }
 
+// GetReadySettings returns the ReadySettings of a managed application with defaults applied to all unset values.
+func GetReadySettings(executable string) ReadySettings {
+	app, _ := GetManagedApp(executable)
+	rs := app.Ready
+	if rs.StableFor <= 0 {
+		rs.StableFor = Duration(DefaultReadyStableFor)
+	}
+	if rs.Timeout <= 0 {
+		rs.Timeout = Duration(DefaultReadyTimeout)
+	}
+	return rs
+}
+
+// GetEnforceSettings returns the EnforceSettings of a managed application with defaults applied to all unset values.
+func GetEnforceSettings(executable string) EnforceSettings {
+	app, _ := GetManagedApp(executable)
+	es := app.Enforce
+	if es.MaxAttempts <= 0 {
+		es.MaxAttempts = DefaultEnforceMaxAttempts
+	}
+	if es.Backoff <= 0 {
+		es.Backoff = Duration(DefaultEnforceBackoff)
+	}
+	if es.Window <= 0 {
+		es.Window = Duration(DefaultEnforceWindow)
+	}
+	return es
+}
+
+// GetApplyOn returns the triggers for applying the settings of a managed application.
+func GetApplyOn(executable string) []string {
+	if app, _ := GetManagedApp(executable); len(app.ApplyOn) > 0 {
+		return app.ApplyOn
+	}
+	return DefaultApplyOn
+}
+
 // getGlobalWindowSettings returns a WindowSettings struct holding the global configuration.
 func getGlobalWindowSettings() WindowSettings {
 	return GetWindowSettingsFromStruct(Config)
@@ -220,3 +691,42 @@ func GetWindowSettingsFromStruct(config Type) WindowSettings {
 		Delay:   config.Global.Delay,
 	}
 }
+
+// UnmarshalTOML reads a Duration from either a duration string like "1.5s" or a number of seconds.
+func (d *Duration) UnmarshalTOML(value any) error {
+	switch v := value.(type) {
+	case int64:
+		*d = Duration(time.Duration(v) * time.Second)
+	case float64:
+		*d = Duration(time.Duration(v * float64(time.Second)))
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*d = Duration(parsed)
+	default:
+		return fmt.Errorf("invalid duration %v, expected a duration like \"750ms\" or seconds", value)
+	}
+	return nil
+}
+
+// MarshalText writes the Duration as a duration string like "750ms".
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// MarshalJSON writes the Duration as a number of seconds, like UnmarshalTOML reads it.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).Seconds())
+}
+
+// UnmarshalJSON reads a Duration from a number of seconds, like UnmarshalTOML.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return err
+	}
+	*d = Duration(time.Duration(seconds * float64(time.Second)))
+	return nil
+}
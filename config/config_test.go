@@ -1,10 +1,15 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
 )
 
 func getPaths() (string, string, string) {
@@ -103,3 +108,192 @@ func TestAddApplication(t *testing.T) {
 		t.Fatalf("%v", err)
 	}
 }
+
+func TestDurationDecode(t *testing.T) {
+	var ws struct {
+		Legacy  WindowSettings `toml:"legacy"`
+		Current WindowSettings `toml:"current"`
+	}
+	content := `
+[legacy]
+delay = 2
+[current]
+delay = "1.5s"
+`
+	if _, err := toml.Decode(content, &ws); err != nil {
+		t.Fatalf("Failed to decode durations: %v", err)
+	}
+
+	if time.Duration(ws.Legacy.Delay) != 2*time.Second {
+		t.Fatalf("Expected integer delay to be read as seconds, got %v", time.Duration(ws.Legacy.Delay))
+	}
+	if time.Duration(ws.Current.Delay) != 1500*time.Millisecond {
+		t.Fatalf("Expected delay of 1.5s, got %v", time.Duration(ws.Current.Delay))
+	}
+
+	if _, err := toml.Decode(`delay = "soon"`, &ws.Current); err == nil {
+		t.Fatal("Expected an invalid duration to fail")
+	}
+}
+
+func TestDurationEncode(t *testing.T) {
+	var sb strings.Builder
+	if err := toml.NewEncoder(&sb).Encode(WindowSettings{Delay: Duration(750 * time.Millisecond)}); err != nil {
+		t.Fatalf("Failed to encode duration: %v", err)
+	}
+	if !strings.Contains(sb.String(), `delay = "750ms"`) {
+		t.Fatalf("Expected delay to be written as a duration string, got:\n%s", sb.String())
+	}
+}
+
+func TestDurationJSON(t *testing.T) {
+	var ws WindowSettings
+	if err := json.Unmarshal([]byte(`{"Delay": 1.5}`), &ws); err != nil {
+		t.Fatalf("Failed to decode duration: %v", err)
+	}
+	if time.Duration(ws.Delay) != 1500*time.Millisecond {
+		t.Fatalf("Expected the number to be read as seconds like in the config file, got %v", time.Duration(ws.Delay))
+	}
+
+	data, err := json.Marshal(WindowSettings{Delay: Duration(750 * time.Millisecond)})
+	if err != nil {
+		t.Fatalf("Failed to encode duration: %v", err)
+	}
+	if !strings.Contains(string(data), `"Delay":0.75`) {
+		t.Fatalf("Expected delay to be written as seconds, got %s", data)
+	}
+}
+
+func TestLayouts(t *testing.T) {
+	movedDir, err := setup()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	Initialize()
+
+	testApp := "TestApp.exe"
+	AddApplication(testApp)
+	app := Config.ManagedApps[testApp]
+	app.Layouts = []Layout{
+		{Name: "wide", Dimensions: WindowSettings{Width: 2560, Height: 1080}},
+		{Name: "left half", Dimensions: WindowSettings{Width: 960, Height: 1080}},
+	}
+	Config.ManagedApps[testApp] = app
+
+	if names := LayoutNames(testApp); strings.Join(names, ",") != "default,wide,left half" {
+		t.Fatalf("Unexpected layout names %v", names)
+	}
+	if next := NextLayout(testApp); next != "wide" {
+		t.Fatalf("Expected the layout after the default one to be wide, got %s", next)
+	}
+	if err := SetActiveLayout(testApp, "missing"); err == nil {
+		t.Fatal("Expected an error selecting a missing layout")
+	}
+	if err := SetActiveLayout(testApp, "left half"); err != nil {
+		t.Fatal(err)
+	}
+	if next := NextLayout(testApp); next != DefaultLayout {
+		t.Fatalf("Expected to wrap around to the default layout, got %s", next)
+	}
+	if ws := GetWindowSettings(testApp); ws.Width != 960 {
+		t.Fatalf("Expected the settings of the active layout, got %+v", ws)
+	}
+	if err := SetDimensions(testApp, WindowSettings{Width: 1000, Height: 1080}); err != nil {
+		t.Fatal(err)
+	}
+
+	Config = Type{}
+	loadConfig()
+	if active := GetActiveLayout(testApp); active != "left half" {
+		t.Fatalf("Expected the active layout to be saved, got %s", active)
+	}
+	if ws := GetWindowSettings(testApp); ws.Width != 1000 {
+		t.Fatalf("Expected the dimensions of the active layout to be replaced, got %+v", ws)
+	}
+	if ws := Config.ManagedApps[testApp].Dimensions; ws.Width == 1000 {
+		t.Fatalf("Expected the default layout to be kept, got %+v", ws)
+	}
+
+	if err := SetActiveLayout(testApp, DefaultLayout); err != nil {
+		t.Fatal(err)
+	}
+	if active := Config.ManagedApps[testApp].ActiveLayout; active != "" {
+		t.Fatalf("Expected the default layout to be saved as empty, got %s", active)
+	}
+
+	if err := cleanup(movedDir); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestSetZoneLayouts(t *testing.T) {
+	movedDir, err := setup()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	Initialize()
+
+	Config.Zones = []ZoneLayout{{Type: "columns", Columns: 2}, {Monitor: 1, Type: "rows", Rows: 2}}
+	if err := SetZoneLayouts([]ZoneLayout{{Monitor: 1, Type: "grid"}}); err == nil {
+		t.Fatal("Expected an error for a grid without rows and columns")
+	}
+	if err := SetZoneLayouts([]ZoneLayout{{Monitor: 2, Type: "columns", Columns: 2}, {Monitor: 2, Type: "columns", Columns: 3}}); err == nil {
+		t.Fatal("Expected an error for two layouts of the same monitor")
+	}
+	if err := SetZoneLayouts([]ZoneLayout{{Monitor: 1, Type: "columns", ColumnWidths: []int{1, 2}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	Config = Type{}
+	loadConfig()
+	if len(Config.Zones) != 2 {
+		t.Fatalf("Expected the layout of all monitors to be kept, got %+v", Config.Zones)
+	}
+	if z, _ := GetZoneLayout(1); z.Type != "columns" || len(z.ColumnWidths) != 2 {
+		t.Fatalf("Expected the layout of monitor 1 to be replaced, got %+v", z)
+	}
+
+	if err := cleanup(movedDir); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestManagedAppsConcurrent(t *testing.T) {
+	movedDir, err := setup()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	Initialize()
+	AddApplication("TestApp.exe")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 20 {
+			if err := SetDimensions("TestApp.exe", WindowSettings{Width: 1000 + i, Height: 1080}); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		if _, ok := GetManagedApp("TestApp.exe"); !ok {
+			t.Error("Expected TestApp.exe to be managed")
+		}
+		GetWindowSettings("TestApp.exe")
+	}
+	<-done
+
+	if executables := ManagedExecutables(); len(executables) != 1 || executables[0] != "TestApp.exe" {
+		t.Fatalf("Unexpected managed executables %v", executables)
+	}
+	if app, _ := GetManagedApp("TestApp.exe"); app.Dimensions.Width != 1019 {
+		t.Fatalf("Expected the last dimensions to be kept, got %+v", app.Dimensions)
+	}
+
+	if err := cleanup(movedDir); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
@@ -0,0 +1,250 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// Modifier is a set of modifier keys. The values match the MOD_* flags of RegisterHotKey.
+type Modifier uint32
+
+const (
+	ModAlt     Modifier = 0x1
+	ModControl Modifier = 0x2
+	ModShift   Modifier = 0x4
+	ModWin     Modifier = 0x8
+)
+
+// modifierKeys maps the virtual key codes of the modifier keys to their Modifier.
+var modifierKeys = map[int]Modifier{
+	VK_CONTROL: ModControl,
+	VK_MENU:    ModAlt,
+	VK_SHIFT:   ModShift,
+	VK_LWIN:    ModWin,
+	VK_RWIN:    ModWin,
+}
+
+// Combo is a hotkey, a key pressed while holding a set of modifiers, e.g. Ctrl+Shift+F4.
+type Combo struct {
+	Modifiers Modifier
+	Key       int
+}
+
+// String returns the combo in the format ParseCombo accepts, with the modifiers in a fixed order.
+func (c Combo) String() string {
+	var parts []string
+	for _, mod := range []struct {
+		flag Modifier
+		name string
+	}{{ModControl, "Ctrl"}, {ModAlt, "Alt"}, {ModShift, "Shift"}, {ModWin, "Win"}} {
+		if c.Modifiers&mod.flag != 0 {
+			parts = append(parts, mod.name)
+		}
+	}
+	return strings.Join(append(parts, KeyName(c.Key)), "+")
+}
+
+// ParseCombo parses a hotkey like "Ctrl+Shift+F4" or "Win+Numpad5". It must contain exactly one key that is not a
+// modifier, which comes last.
+//
+// Returns either the Combo or an error if a key is unknown or the combo has no or more than one other key.
+func ParseCombo(s string) (Combo, error) {
+	chord, err := ParseChord(s)
+	if err != nil {
+		return Combo{}, err
+	}
+
+	var combo Combo
+	for i, vk := range chord {
+		if mod, ok := modifierKeys[vk]; ok && i < len(chord)-1 {
+			combo.Modifiers |= mod
+			continue
+		}
+		if i < len(chord)-1 {
+			return Combo{}, fmt.Errorf("hotkey %q can only have one key besides the modifiers", s)
+		}
+		if _, ok := modifierKeys[vk]; ok {
+			return Combo{}, fmt.Errorf("hotkey %q needs a key besides the modifiers", s)
+		}
+		combo.Key = vk
+	}
+	return combo, nil
+}
+
+var (
+	// ErrHotkeyConflict is returned when binding a combo that is already bound to another action.
+	ErrHotkeyConflict = errors.New("hotkey is already bound")
+	// ErrHotkeyTaken is returned by a Source if another application registered the combo already.
+	ErrHotkeyTaken = errors.New("hotkey is registered by another application")
+)
+
+// Source delivers the presses of registered hotkeys. Presses are key-down edges, holding a combo reports it once.
+type Source interface {
+	// Register starts reporting presses of the combo with the given id.
+	Register(id int, combo Combo) error
+	// Unregister stops reporting presses of the hotkey with the given id.
+	Unregister(id int) error
+	// Presses returns the channel the ids of pressed hotkeys are sent on.
+	Presses() <-chan int
+}
+
+// binding is an action bound to a hotkey.
+type binding struct {
+	combo   Combo
+	action  string
+	handler func()
+	running bool
+}
+
+// Hotkeys is a registry of hotkeys bound to named actions. Handlers run outside of the registry, a press of a hotkey
+// whose handler is still running is ignored.
+type Hotkeys struct {
+	mu       sync.Mutex
+	source   Source
+	bindings map[int]*binding
+	nextID   int
+}
+
+// NewHotkeys returns a registry that registers its hotkeys with the given source.
+func NewHotkeys(source Source) *Hotkeys {
+	return &Hotkeys{
+		source:   source,
+		bindings: make(map[int]*binding),
+		nextID:   1,
+	}
+}
+
+// Bind binds the action to the combo and registers it with the source.
+//
+// Returns an error wrapping ErrHotkeyConflict if the combo is already bound, or the error of the source if it could
+// not be registered, e.g. ErrHotkeyTaken.
+func (h *Hotkeys) Bind(combo Combo, action string, handler func()) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, b := range h.bindings {
+		if b.combo == combo {
+			return fmt.Errorf("%w: %s is bound to %s, can't bind it to %s", ErrHotkeyConflict, combo, b.action, action)
+		}
+	}
+
+	id := h.nextID
+	if err := h.source.Register(id, combo); err != nil {
+		return fmt.Errorf("failed to register %s for %s: %w", combo, action, err)
+	}
+	h.nextID++
+	h.bindings[id] = &binding{combo: combo, action: action, handler: handler}
+	return nil
+}
+
+// UnbindAll removes all bindings, e.g. before binding the hotkeys of a reloaded config.
+func (h *Hotkeys) UnbindAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for id, b := range h.bindings {
+		if err := h.source.Unregister(id); err != nil {
+			log.Printf("Failed to unregister %s: %v\n", b.combo, err)
+		}
+		delete(h.bindings, id)
+	}
+}
+
+// Run calls the handlers of pressed hotkeys until the context is cancelled.
+func (h *Hotkeys) Run(ctx context.Context) {
+	presses := h.source.Presses()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-presses:
+			h.press(id)
+		}
+	}
+}
+
+// press starts the handler of the hotkey unless it is unknown or its handler is still running.
+func (h *Hotkeys) press(id int) {
+	h.mu.Lock()
+	b, ok := h.bindings[id]
+	if !ok || b.running {
+		h.mu.Unlock()
+		return
+	}
+	b.running = true
+	h.mu.Unlock()
+
+	log.Printf("Hotkey %s pressed, running %s\n", b.combo, b.action)
+	go func() {
+		defer func() {
+			h.mu.Lock()
+			b.running = false
+			h.mu.Unlock()
+		}()
+		b.handler()
+	}()
+}
+
+// FakeSource is a Source for tests, presses are simulated with Press.
+type FakeSource struct {
+	mu      sync.Mutex
+	combos  map[int]Combo
+	taken   map[Combo]bool
+	presses chan int
+}
+
+// NewFakeSource returns a FakeSource, combos taken by other applications can't be registered.
+func NewFakeSource(taken ...Combo) *FakeSource {
+	f := &FakeSource{
+		combos:  make(map[int]Combo),
+		taken:   make(map[Combo]bool),
+		presses: make(chan int),
+	}
+	for _, combo := range taken {
+		f.taken[combo] = true
+	}
+	return f
+}
+
+func (f *FakeSource) Register(id int, combo Combo) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.taken[combo] {
+		return ErrHotkeyTaken
+	}
+	f.combos[id] = combo
+	return nil
+}
+
+func (f *FakeSource) Unregister(id int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.combos, id)
+	return nil
+}
+
+func (f *FakeSource) Presses() <-chan int {
+	return f.presses
+}
+
+// Press simulates pressing the combo and reports whether it is registered. It blocks until the press was received.
+func (f *FakeSource) Press(combo Combo) bool {
+	f.mu.Lock()
+	id := 0
+	for registered, c := range f.combos {
+		if c == combo {
+			id = registered
+		}
+	}
+	f.mu.Unlock()
+
+	if id == 0 {
+		return false
+	}
+	f.presses <- id
+	return true
+}
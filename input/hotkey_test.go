@@ -0,0 +1,112 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCombo(t *testing.T) {
+	tests := map[string]Combo{
+		"Ctrl+Shift+F4": {Modifiers: ModControl | ModShift, Key: VK_F4},
+		"win+numpad5":   {Modifiers: ModWin, Key: VK_NUMPAD0 + 5},
+		"Alt+Ctrl+F":    {Modifiers: ModControl | ModAlt, Key: 'F'},
+		"F4":            {Key: VK_F4},
+	}
+	for s, expected := range tests {
+		if combo, err := ParseCombo(s); err != nil || combo != expected {
+			t.Errorf("%q: expected %v, got %v (%v)", s, expected, combo, err)
+		}
+	}
+
+	for _, s := range []string{"", "Ctrl+Shift", "Ctrl+A+B", "F4+Ctrl", "Ctrl+Hyper"} {
+		if _, err := ParseCombo(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
+
+func TestComboString(t *testing.T) {
+	combo, err := ParseCombo("Shift+Win+Alt+Ctrl+Numpad5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := combo.String(); s != "Ctrl+Alt+Shift+Win+Numpad5" {
+		t.Fatalf("Expected the modifiers in a fixed order, got %q", s)
+	}
+}
+
+// runHotkeys binds the registry to a fake source and runs it for the duration of the test.
+func runHotkeys(t *testing.T, taken ...Combo) (*Hotkeys, *FakeSource) {
+	source := NewFakeSource(taken...)
+	hotkeys := NewHotkeys(source)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go hotkeys.Run(ctx)
+	return hotkeys, source
+}
+
+func TestHotkeysBind(t *testing.T) {
+	toggle := Combo{Modifiers: ModControl, Key: VK_F4}
+	taken := Combo{Key: VK_F1}
+	hotkeys, source := runHotkeys(t, taken)
+
+	called := make(chan string, 1)
+	if err := hotkeys.Bind(toggle, "toggle-app", func() { called <- "toggle-app" }); err != nil {
+		t.Fatal(err)
+	}
+	if err := hotkeys.Bind(toggle, "other", func() {}); !errors.Is(err, ErrHotkeyConflict) {
+		t.Fatalf("Expected a conflict, got %v", err)
+	}
+	if err := hotkeys.Bind(taken, "other", func() {}); !errors.Is(err, ErrHotkeyTaken) {
+		t.Fatalf("Expected the hotkey to be taken, got %v", err)
+	}
+
+	if !source.Press(toggle) {
+		t.Fatal("Expected the hotkey to be registered")
+	}
+	select {
+	case action := <-called:
+		if action != "toggle-app" {
+			t.Fatalf("Unexpected action %s", action)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the action to run")
+	}
+
+	hotkeys.UnbindAll()
+	if source.Press(toggle) {
+		t.Fatal("Expected the hotkey to be unregistered")
+	}
+}
+
+func TestHotkeysIgnoresPressWhileRunning(t *testing.T) {
+	combo := Combo{Key: VK_F4}
+	hotkeys, source := runHotkeys(t)
+
+	started := make(chan struct{}, 3)
+	release := make(chan struct{})
+	if err := hotkeys.Bind(combo, "toggle-app", func() {
+		started <- struct{}{}
+		<-release
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// presses are handled in order, so once the probe was received all presses of the combo were handled
+	probe := Combo{Key: VK_F1}
+	if err := hotkeys.Bind(probe, "probe", func() {}); err != nil {
+		t.Fatal(err)
+	}
+	source.Press(combo)
+	source.Press(combo)
+	source.Press(combo)
+	source.Press(probe)
+	<-started
+	close(release)
+
+	if n := len(started); n != 0 {
+		t.Fatalf("Expected a single run while the handler is running, got %d more", n)
+	}
+}
@@ -0,0 +1,106 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"syscall"
+
+	"github.com/lxn/win"
+)
+
+const (
+	MOD_NOREPEAT                    = 0x4000
+	ERROR_HOTKEY_ALREADY_REGISTERED = 1409
+
+	// wmHotkeyRequest wakes the message loop of a systemSource to run a pending request.
+	wmHotkeyRequest = win.WM_APP + 1
+)
+
+var (
+	procRegisterHotKey    = user32.NewProc("RegisterHotKey")
+	procUnregisterHotKey  = user32.NewProc("UnregisterHotKey")
+	procPostThreadMessage = user32.NewProc("PostThreadMessageW")
+)
+
+// systemSource implements Source with hotkeys registered with the system. Hotkeys belong to the thread registering
+// them and are posted to its message queue, so a single locked thread registers all of them and runs the message
+// loop. They are registered with MOD_NOREPEAT, so holding a combo doesn't repeat it.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-registerhotkey
+type systemSource struct {
+	presses  chan int
+	requests chan func()
+	threadID uint32
+}
+
+// NewSystemSource starts the message loop of a Source backed by system hotkeys.
+func NewSystemSource() Source {
+	s := &systemSource{
+		presses:  make(chan int, 8),
+		requests: make(chan func()),
+	}
+	started := make(chan struct{})
+	go s.loop(started)
+	<-started
+	return s
+}
+
+func (s *systemSource) loop(started chan struct{}) {
+	runtime.LockOSThread()
+	s.threadID = win.GetCurrentThreadId()
+	// a thread only gets a message queue once it calls a message function
+	var msg win.MSG
+	win.PeekMessage(&msg, 0, 0, 0, win.PM_NOREMOVE)
+	close(started)
+
+	for win.GetMessage(&msg, 0, 0, 0) > 0 {
+		switch msg.Message {
+		case win.WM_HOTKEY:
+			select {
+			case s.presses <- int(msg.WParam):
+			default:
+				log.Println("Dropping hotkey press, too many presses are pending.")
+			}
+		case wmHotkeyRequest:
+			(<-s.requests)()
+		}
+	}
+}
+
+// do runs f on the thread of the message loop.
+func (s *systemSource) do(f func() error) error {
+	if ret, _, err := procPostThreadMessage.Call(uintptr(s.threadID), wmHotkeyRequest, 0, 0); ret == 0 {
+		return fmt.Errorf("failed to reach the hotkey thread: %v", err)
+	}
+	done := make(chan error, 1)
+	s.requests <- func() { done <- f() }
+	return <-done
+}
+
+func (s *systemSource) Register(id int, combo Combo) error {
+	return s.do(func() error {
+		ret, _, err := procRegisterHotKey.Call(0, uintptr(id), uintptr(combo.Modifiers|MOD_NOREPEAT), uintptr(combo.Key))
+		if ret != 0 {
+			return nil
+		}
+		if errors.Is(err, syscall.Errno(ERROR_HOTKEY_ALREADY_REGISTERED)) {
+			return ErrHotkeyTaken
+		}
+		return err
+	})
+}
+
+func (s *systemSource) Unregister(id int) error {
+	return s.do(func() error {
+		if ret, _, err := procUnregisterHotKey.Call(0, uintptr(id)); ret == 0 {
+			return err
+		}
+		return nil
+	})
+}
+
+func (s *systemSource) Presses() <-chan int {
+	return s.presses
+}
@@ -1,25 +1,204 @@
 package input
 
 import (
-	"syscall"
-)
-
-var (
-	user32               = syscall.NewLazyDLL("user32.dll")
-	procGetAsyncKeyState = user32.NewProc("GetAsyncKeyState")
+	"fmt"
+	"strings"
 )
 
 // Define virtual key codes for the keys you are interested in.
 const (
+	VK_BACK      = 0x08
+	VK_TAB       = 0x09
+	VK_RETURN    = 0x0D
+	VK_SHIFT     = 0x10
+	VK_CONTROL   = 0x11
+	VK_MENU      = 0x12 // Alt key virtual key code
+	VK_PAUSE     = 0x13
+	VK_ESCAPE    = 0x1B
+	VK_SPACE     = 0x20
+	VK_PRIOR     = 0x21 // Page up
+	VK_NEXT      = 0x22 // Page down
+	VK_END       = 0x23
+	VK_HOME      = 0x24
+	VK_LEFT      = 0x25
+	VK_UP        = 0x26
+	VK_RIGHT     = 0x27
+	VK_DOWN      = 0x28
+	VK_SNAPSHOT  = 0x2C // Print screen
+	VK_INSERT    = 0x2D
+	VK_DELETE    = 0x2E
+	VK_LWIN      = 0x5B
+	VK_RWIN      = 0x5C
+	VK_NUMPAD0   = 0x60 // Numpad digits follow in order up to VK_NUMPAD0 + 9
+	VK_MULTIPLY  = 0x6A
+	VK_ADD       = 0x6B
+	VK_SUBTRACT  = 0x6D
+	VK_DECIMAL   = 0x6E
+	VK_NUM_SLASH = 0x6F // Numpad Slash virtual key code
+	VK_F1        = 0x70
 	VK_F3        = 0x72 // F3 key virtual key code
 	VK_F4        = 0x73 // F4 key virtual key code
-	VK_NUM_SLASH = 0x6F // Numpad Slash virtual key code
-	// Add other keys as needed
+	VK_F24       = 0x87
 )
 
-// IsKeyPressed checks if a key is currently pressed.
-// Returns true if the key is pressed, false otherwise.
-func IsKeyPressed(vkCode int) bool {
-	keyState, _, _ := procGetAsyncKeyState.Call(uintptr(vkCode))
-	return keyState&0x8000 != 0
+// keyNames maps the names that can be used in key sequences to virtual key codes. Letters, digits and function keys
+// are handled by ParseKey directly.
+var keyNames = map[string]int{
+	"BACKSPACE": VK_BACK,
+	"TAB":       VK_TAB,
+	"ENTER":     VK_RETURN,
+	"RETURN":    VK_RETURN,
+	"SHIFT":     VK_SHIFT,
+	"CTRL":      VK_CONTROL,
+	"CONTROL":   VK_CONTROL,
+	"ALT":       VK_MENU,
+	"PAUSE":     VK_PAUSE,
+	"ESC":       VK_ESCAPE,
+	"ESCAPE":    VK_ESCAPE,
+	"SPACE":     VK_SPACE,
+	"PAGEUP":    VK_PRIOR,
+	"PAGEDOWN":  VK_NEXT,
+	"END":       VK_END,
+	"HOME":      VK_HOME,
+	"LEFT":      VK_LEFT,
+	"UP":        VK_UP,
+	"RIGHT":     VK_RIGHT,
+	"DOWN":      VK_DOWN,
+	"PRINT":     VK_SNAPSHOT,
+	"INSERT":    VK_INSERT,
+	"DELETE":    VK_DELETE,
+	"DEL":       VK_DELETE,
+	"WIN":       VK_LWIN,
+	"NUMSLASH":  VK_NUM_SLASH,
+	"NUMSTAR":   VK_MULTIPLY,
+	"NUMPLUS":   VK_ADD,
+	"NUMMINUS":  VK_SUBTRACT,
+	"NUMDOT":    VK_DECIMAL,
+}
+
+// canonicalKeyNames are the names KeyName returns for keys with several names in keyNames.
+var canonicalKeyNames = map[int]string{
+	VK_BACK:      "Backspace",
+	VK_TAB:       "Tab",
+	VK_RETURN:    "Enter",
+	VK_SHIFT:     "Shift",
+	VK_CONTROL:   "Ctrl",
+	VK_MENU:      "Alt",
+	VK_PAUSE:     "Pause",
+	VK_ESCAPE:    "Esc",
+	VK_SPACE:     "Space",
+	VK_PRIOR:     "PageUp",
+	VK_NEXT:      "PageDown",
+	VK_END:       "End",
+	VK_HOME:      "Home",
+	VK_LEFT:      "Left",
+	VK_UP:        "Up",
+	VK_RIGHT:     "Right",
+	VK_DOWN:      "Down",
+	VK_SNAPSHOT:  "Print",
+	VK_INSERT:    "Insert",
+	VK_DELETE:    "Delete",
+	VK_LWIN:      "Win",
+	VK_NUM_SLASH: "NumSlash",
+	VK_MULTIPLY:  "NumStar",
+	VK_ADD:       "NumPlus",
+	VK_SUBTRACT:  "NumMinus",
+	VK_DECIMAL:   "NumDot",
+}
+
+// Chord is a set of keys that are pressed together, e.g. Alt+Enter. The keys are pressed in order and released in
+// reverse order.
+type Chord []int
+
+// Sequence is a list of chords that are pressed one after another.
+type Sequence []Chord
+
+// ParseKey returns the virtual key code of the key with the given name, e.g. "Enter", "F11", "Numpad5", "A" or "7".
+// Names are case-insensitive.
+//
+// Returns either the virtual key code or an error if the name is unknown.
+func ParseKey(name string) (int, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if vk, ok := keyNames[name]; ok {
+		return vk, nil
+	}
+
+	if len(name) == 1 && (name[0] >= 'A' && name[0] <= 'Z' || name[0] >= '0' && name[0] <= '9') {
+		return int(name[0]), nil // letters and digits use their ASCII code
+	}
+
+	var n int
+	if _, err := fmt.Sscanf(name, "F%d", &n); err == nil && fmt.Sprintf("F%d", n) == name && n >= 1 && n <= 24 {
+		return VK_F1 + n - 1, nil
+	}
+	if _, err := fmt.Sscanf(name, "NUMPAD%d", &n); err == nil && fmt.Sprintf("NUMPAD%d", n) == name && n >= 0 && n <= 9 {
+		return VK_NUMPAD0 + n, nil
+	}
+
+	return 0, fmt.Errorf("unknown key %q", name)
+}
+
+// KeyName returns the name of the key with the given virtual key code that ParseKey accepts, e.g. "F4", or its code in
+// hex for keys without a name.
+func KeyName(vk int) string {
+	switch {
+	case vk >= 'A' && vk <= 'Z' || vk >= '0' && vk <= '9':
+		return string(rune(vk))
+	case vk >= VK_F1 && vk <= VK_F24:
+		return fmt.Sprintf("F%d", vk-VK_F1+1)
+	case vk >= VK_NUMPAD0 && vk <= VK_NUMPAD0+9:
+		return fmt.Sprintf("Numpad%d", vk-VK_NUMPAD0)
+	}
+	if name, ok := canonicalKeyNames[vk]; ok {
+		return name
+	}
+	return fmt.Sprintf("%#x", vk)
+}
+
+// ParseChord parses keys joined by "+", e.g. "Ctrl+Shift+F11".
+//
+// Returns either the Chord or an error if a key is unknown.
+func ParseChord(s string) (Chord, error) {
+	var chord Chord
+	for _, name := range strings.Split(s, "+") {
+		vk, err := ParseKey(name)
+		if err != nil {
+			return nil, err
+		}
+		chord = append(chord, vk)
+	}
+	return chord, nil
+}
+
+// ParseSequence parses chords separated by spaces or commas, e.g. "Alt+Enter" or "Esc, Alt+Enter".
+//
+// Returns either the Sequence or an error if it is empty or contains an unknown key.
+func ParseSequence(s string) (Sequence, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty key sequence")
+	}
+
+	var seq Sequence
+	for _, field := range fields {
+		chord, err := ParseChord(field)
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, chord)
+	}
+	return seq, nil
+}
+
+// Injector sends synthetic key presses to the window that has focus.
+type Injector interface {
+	SendKeys(seq Sequence) error
+}
+
+// injector is the Injector used by SendKeys.
+var injector Injector = newInjector()
+
+// SendKeys presses and releases the chords of the sequence one after another.
+func SendKeys(seq Sequence) error {
+	return injector.SendKeys(seq)
 }
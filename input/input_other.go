@@ -0,0 +1,44 @@
+//go:build !windows
+
+package input
+
+import (
+	"errors"
+)
+
+// IsKeyPressed checks if a key is currently pressed, which is not supported on this platform.
+func IsKeyPressed(vkCode int) bool {
+	return false
+}
+
+type unsupportedInjector struct{}
+
+func newInjector() Injector {
+	return unsupportedInjector{}
+}
+
+func (unsupportedInjector) SendKeys(seq Sequence) error {
+	return errors.New("sending keys is not supported on this platform")
+}
+
+// errUnsupportedHotkeys is returned when registering hotkeys on platforms without system hotkeys.
+var errUnsupportedHotkeys = errors.New("hotkeys are not supported on this platform")
+
+type unsupportedSource struct{}
+
+// NewSystemSource returns a Source that can't register any hotkeys.
+func NewSystemSource() Source {
+	return unsupportedSource{}
+}
+
+func (unsupportedSource) Register(id int, combo Combo) error {
+	return errUnsupportedHotkeys
+}
+
+func (unsupportedSource) Unregister(id int) error {
+	return errUnsupportedHotkeys
+}
+
+func (unsupportedSource) Presses() <-chan int {
+	return nil
+}
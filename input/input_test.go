@@ -0,0 +1,55 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := map[string]int{
+		"Enter": VK_RETURN,
+		"alt":   VK_MENU,
+		" F4 ":  VK_F4,
+		"f24":   VK_F24,
+		"a":     'A',
+		"7":     '7',
+		"Del":   VK_DELETE,
+	}
+	for name, expected := range tests {
+		if vk, err := ParseKey(name); err != nil || vk != expected {
+			t.Errorf("%q: expected %#x, got %#x (%v)", name, expected, vk, err)
+		}
+	}
+
+	for _, name := range []string{"", "F0", "F25", "F01", "AB", "Hyper"} {
+		if _, err := ParseKey(name); err == nil {
+			t.Errorf("%q: expected an error", name)
+		}
+	}
+}
+
+func TestParseSequence(t *testing.T) {
+	seq, err := ParseSequence("Esc, Alt+Enter  Ctrl+Shift+F11")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Sequence{{VK_ESCAPE}, {VK_MENU, VK_RETURN}, {VK_CONTROL, VK_SHIFT, VK_F1 + 10}}
+	if !reflect.DeepEqual(seq, expected) {
+		t.Fatalf("Expected %v, got %v", expected, seq)
+	}
+
+	for _, s := range []string{"", " , ", "Alt+", "Alt+Foo"} {
+		if _, err := ParseSequence(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
+
+func TestKeyName(t *testing.T) {
+	for _, vk := range []int{VK_RETURN, VK_F4, VK_F24, VK_NUMPAD0 + 5, VK_NUM_SLASH, VK_LWIN, 'A', '7'} {
+		name := KeyName(vk)
+		if parsed, err := ParseKey(name); err != nil || parsed != vk {
+			t.Errorf("%#x: expected %q to parse back, got %#x (%v)", vk, name, parsed, err)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package input
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+var (
+	user32               = syscall.NewLazyDLL("user32.dll")
+	procGetAsyncKeyState = user32.NewProc("GetAsyncKeyState")
+)
+
+// IsKeyPressed checks if a key is currently pressed.
+// Returns true if the key is pressed, false otherwise.
+func IsKeyPressed(vkCode int) bool {
+	keyState, _, _ := procGetAsyncKeyState.Call(uintptr(vkCode))
+	return keyState&0x8000 != 0
+}
+
+// keyboardInput is an INPUT structure holding a KEYBDINPUT. It is padded to the size of INPUT, whose union is as
+// large as the bigger MOUSEINPUT.
+type keyboardInput struct {
+	Type uint32
+	Ki   win.KEYBDINPUT
+	_    [8]byte
+}
+
+// extendedKeys are the keys that need KEYEVENTF_EXTENDEDKEY to not be taken for their numpad counterparts.
+var extendedKeys = map[int]bool{
+	VK_PRIOR: true, VK_NEXT: true, VK_END: true, VK_HOME: true, VK_LEFT: true, VK_UP: true, VK_RIGHT: true,
+	VK_DOWN: true, VK_INSERT: true, VK_DELETE: true, VK_NUM_SLASH: true,
+}
+
+// sendInputInjector implements Injector using SendInput.
+type sendInputInjector struct{}
+
+func newInjector() Injector {
+	return sendInputInjector{}
+}
+
+// SendKeys presses and releases the chords of the sequence one after another.
+//
+// This function uses the SendInput function from winuser.h.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-sendinput
+func (sendInputInjector) SendKeys(seq Sequence) error {
+	var inputs []keyboardInput
+	for _, chord := range seq {
+		for _, vk := range chord {
+			inputs = append(inputs, keyInput(vk, 0))
+		}
+		for i := len(chord) - 1; i >= 0; i-- {
+			inputs = append(inputs, keyInput(chord[i], win.KEYEVENTF_KEYUP))
+		}
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	sent := win.SendInput(uint32(len(inputs)), unsafe.Pointer(&inputs[0]), int32(unsafe.Sizeof(inputs[0])))
+	if int(sent) != len(inputs) {
+		return fmt.Errorf("only %d of %d key events were sent, input might be blocked by another process", sent, len(inputs))
+	}
+	return nil
+}
+
+func keyInput(vk int, flags uint32) keyboardInput {
+	if extendedKeys[vk] {
+		flags |= win.KEYEVENTF_EXTENDEDKEY
+	}
+	return keyboardInput{Type: win.INPUT_KEYBOARD, Ki: win.KEYBDINPUT{WVk: uint16(vk), DwFlags: flags}}
+}
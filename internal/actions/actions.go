@@ -0,0 +1,317 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnknownAction is returned when invoking an action that is not registered.
+var ErrUnknownAction = errors.New("unknown action")
+
+// ArgType is the type of an argument of an action.
+type ArgType int
+
+const (
+	ArgString ArgType = iota
+	ArgInt
+	ArgDuration // e.g. "15m" or "1h30m"
+)
+
+func (t ArgType) String() string {
+	switch t {
+	case ArgString:
+		return "string"
+	case ArgInt:
+		return "int"
+	case ArgDuration:
+		return "duration"
+	default:
+		return "unknown"
+	}
+}
+
+// Arg describes an argument of an action. Optional arguments must come after the required ones.
+type Arg struct {
+	Name     string
+	Type     ArgType
+	Optional bool
+}
+
+// Action is a named operation that hotkeys, the tray, the GUI, the IPC API and the CLI run in the same way.
+type Action struct {
+	Name        string
+	Description string
+	Args        []Arg
+	Run         func(args Args) error
+}
+
+// Usage returns the action with its arguments as they are written in an invocation, e.g. "apply [executable]".
+func (a Action) Usage() string {
+	parts := []string{a.Name}
+	for _, arg := range a.Args {
+		if arg.Optional {
+			parts = append(parts, "["+arg.Name+"]")
+		} else {
+			parts = append(parts, "<"+arg.Name+">")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Args are the typed arguments of an invocation by name. Optional arguments that were not given are missing.
+type Args map[string]any
+
+// Has reports whether the argument was given.
+func (a Args) Has(name string) bool {
+	_, ok := a[name]
+	return ok
+}
+
+// String returns the string argument with the given name or "" if it was not given.
+func (a Args) String(name string) string {
+	s, _ := a[name].(string)
+	return s
+}
+
+// Int returns the int argument with the given name or 0 if it was not given.
+func (a Args) Int(name string) int {
+	n, _ := a[name].(int)
+	return n
+}
+
+// Duration returns the duration argument with the given name or 0 if it was not given.
+func (a Args) Duration(name string) time.Duration {
+	d, _ := a[name].(time.Duration)
+	return d
+}
+
+// bind converts the raw arguments of an invocation to the types of the action's arguments.
+func (a Action) bind(raw []string) (Args, error) {
+	if len(raw) > len(a.Args) {
+		return nil, fmt.Errorf("too many arguments for %s, usage: %s", a.Name, a.Usage())
+	}
+
+	args := make(Args, len(raw))
+	for i, arg := range a.Args {
+		if i >= len(raw) {
+			if !arg.Optional {
+				return nil, fmt.Errorf("missing argument %s for %s, usage: %s", arg.Name, a.Name, a.Usage())
+			}
+			continue
+		}
+
+		switch arg.Type {
+		case ArgString:
+			args[arg.Name] = raw[i]
+		case ArgInt:
+			n, err := strconv.Atoi(raw[i])
+			if err != nil {
+				return nil, fmt.Errorf("argument %s of %s must be a number, got %q", arg.Name, a.Name, raw[i])
+			}
+			args[arg.Name] = n
+		case ArgDuration:
+			d, err := time.ParseDuration(raw[i])
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("argument %s of %s must be a duration like 15m, got %q", arg.Name, a.Name, raw[i])
+			}
+			args[arg.Name] = d
+		}
+	}
+	return args, nil
+}
+
+// Invocation is a parsed call of an action by name with its raw arguments.
+type Invocation struct {
+	Name string
+	Args []string
+}
+
+// String returns the invocation in the format Parse accepts. Arguments containing spaces or quotes are quoted.
+func (inv Invocation) String() string {
+	parts := []string{inv.Name}
+	for _, arg := range inv.Args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"") {
+			arg = quote(arg)
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// quote puts the argument in double quotes the way split reads it back. Only quotes and the backslashes right before
+// them are escaped, so Windows paths keep their single backslashes.
+func quote(arg string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	backslashes := 0
+	for i := 0; i < len(arg); i++ {
+		switch arg[i] {
+		case '\\':
+			backslashes++
+		case '"':
+			b.WriteString(strings.Repeat(`\`, backslashes+1))
+			backslashes = 0
+		default:
+			backslashes = 0
+		}
+		b.WriteByte(arg[i])
+	}
+	b.WriteString(strings.Repeat(`\`, backslashes))
+	b.WriteByte('"')
+	return b.String()
+}
+
+// Parse parses an invocation like "apply", "launch:Game.exe" or `apply "My Game.exe"`. The first argument can be
+// joined to the name with a colon, arguments containing spaces can be quoted. Like on the Windows command line, a
+// quote is escaped with a backslash and backslashes are only escaped right before a quote, e.g. `"C:\My Games\\"`.
+//
+// Returns either the Invocation or an error if it is empty or a quote is not closed.
+func Parse(s string) (Invocation, error) {
+	fields, err := split(s)
+	if err != nil {
+		return Invocation{}, err
+	}
+	if len(fields) == 0 {
+		return Invocation{}, errors.New("empty action")
+	}
+
+	name, arg, found := strings.Cut(fields[0], ":")
+	inv := Invocation{Name: name}
+	if found {
+		inv.Args = append(inv.Args, arg)
+	}
+	inv.Args = append(inv.Args, fields[1:]...)
+	return inv, nil
+}
+
+// split splits s at whitespace outside of double quotes. A run of backslashes followed by a quote is halved, an odd
+// one escapes the quote. Other backslashes are kept as they are.
+func split(s string) ([]string, error) {
+	var fields []string
+	var field strings.Builder
+	inField, quoted := false, false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\':
+			n := 1
+			for i+n < len(s) && s[i+n] == '\\' {
+				n++
+			}
+			i += n - 1
+			inField = true
+			if i+1 >= len(s) || s[i+1] != '"' {
+				field.WriteString(strings.Repeat(`\`, n))
+				continue
+			}
+			field.WriteString(strings.Repeat(`\`, n/2))
+			if n%2 == 1 {
+				field.WriteByte('"')
+				i++
+			}
+		case c == '"':
+			quoted = !quoted
+			inField = true
+		case !quoted && (c == ' ' || c == '\t'):
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteByte(c)
+			inField = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unclosed quote in %q", s)
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	return fields, nil
+}
+
+var registry = struct {
+	sync.RWMutex
+	actions map[string]Action
+}{actions: make(map[string]Action)}
+
+// Register adds the action to the registry. Registering two actions with the same name is a programming error and
+// panics.
+func Register(action Action) {
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.actions[action.Name]; ok {
+		panic(fmt.Sprintf("action %s is registered twice", action.Name))
+	}
+	registry.actions[action.Name] = action
+}
+
+// Lookup returns the action with the given name.
+func Lookup(name string) (Action, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	action, ok := registry.actions[name]
+	return action, ok
+}
+
+// List returns all registered actions sorted by name.
+func List() []Action {
+	registry.RLock()
+	defer registry.RUnlock()
+	list := make([]Action, 0, len(registry.actions))
+	for _, action := range registry.actions {
+		list = append(list, action)
+	}
+	slices.SortFunc(list, func(a, b Action) int { return strings.Compare(a.Name, b.Name) })
+	return list
+}
+
+// prepare parses the invocation and binds its arguments to the registered action.
+func prepare(invocation string) (Action, Args, error) {
+	inv, err := Parse(invocation)
+	if err != nil {
+		return Action{}, nil, err
+	}
+	action, ok := Lookup(inv.Name)
+	if !ok {
+		return Action{}, nil, fmt.Errorf("%w %q", ErrUnknownAction, inv.Name)
+	}
+	args, err := action.bind(inv.Args)
+	if err != nil {
+		return Action{}, nil, err
+	}
+	return action, args, nil
+}
+
+// Check reports whether the invocation refers to a registered action with valid arguments, without running it, e.g.
+// to validate the hotkeys of the config.
+func Check(invocation string) error {
+	_, _, err := prepare(invocation)
+	return err
+}
+
+// Run runs the invocation, source names what invoked it (e.g. "hotkey" or "tray") for the log. Every error is
+// logged, so callers only need to handle it if they report it elsewhere, e.g. to the CLI.
+func Run(source string, invocation string) error {
+	action, args, err := prepare(invocation)
+	if err != nil {
+		log.Printf("Invalid action %q from %s: %v\n", invocation, source, err)
+		return err
+	}
+
+	log.Printf("Running %s from %s\n", invocation, source)
+	if err := action.Run(args); err != nil {
+		log.Printf("Error running %s from %s: %v\n", invocation, source, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package actions
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]Invocation{
+		"apply":                      {Name: "apply"},
+		"  apply   Game.exe ":        {Name: "apply", Args: []string{"Game.exe"}},
+		"launch:Game.exe":            {Name: "launch", Args: []string{"Game.exe"}},
+		`launch:"C:\My Games\a.exe"`: {Name: "launch", Args: []string{`C:\My Games\a.exe`}},
+		`span-zones 1-2 ""`:          {Name: "span-zones", Args: []string{"1-2", ""}},
+		`launch "C:\Games\\" x`:      {Name: "launch", Args: []string{`C:\Games\`, "x"}},
+		`launch "say \"hi\""`:        {Name: "launch", Args: []string{`say "hi"`}},
+		`launch \\server\a.exe`:      {Name: "launch", Args: []string{`\\server\a.exe`}},
+	}
+	for s, expected := range tests {
+		inv, err := Parse(s)
+		if err != nil || !reflect.DeepEqual(inv, expected) {
+			t.Errorf("%q: expected %+v, got %+v (%v)", s, expected, inv, err)
+		}
+	}
+
+	for _, s := range []string{"", "   ", `apply "Game.exe`} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
+
+func TestInvocationString(t *testing.T) {
+	for _, arg := range []string{
+		"My Game.exe",
+		`C:\My Games\a.exe`,
+		`C:\My Games\`,
+		`\\server\My Share\a.exe`,
+		`say "hi"`,
+		`C:\Temp\"quoted\\"`,
+		"",
+	} {
+		inv := Invocation{Name: "launch", Args: []string{arg, "--fullscreen"}}
+		parsed, err := Parse(inv.String())
+		if err != nil || !reflect.DeepEqual(parsed, inv) {
+			t.Errorf("Expected %s to parse back to %q, got %q (%v)", inv.String(), arg, parsed.Args, err)
+		}
+	}
+
+	if s := (Invocation{Name: "launch", Args: []string{`C:\My Games\a.exe`}}).String(); s != `launch "C:\My Games\a.exe"` {
+		t.Fatalf("Expected the backslashes of the path to be kept, got %s", s)
+	}
+}
+
+func TestBind(t *testing.T) {
+	action := Action{Name: "test", Args: []Arg{
+		{Name: "zone", Type: ArgInt},
+		{Name: "for", Type: ArgDuration, Optional: true},
+		{Name: "executable", Type: ArgString, Optional: true},
+	}}
+
+	args, err := action.bind([]string{"2", "15m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args.Int("zone") != 2 || args.Duration("for") != 15*time.Minute || args.Has("executable") {
+		t.Fatalf("Unexpected args %v", args)
+	}
+
+	for _, raw := range [][]string{nil, {"two"}, {"2", "soon"}, {"2", "15m", "Game.exe", "extra"}} {
+		if _, err := action.bind(raw); err == nil {
+			t.Errorf("%q: expected an error", raw)
+		}
+	}
+}
+
+// register registers the action for the duration of the test.
+func register(t *testing.T, action Action) {
+	t.Helper()
+	Register(action)
+	t.Cleanup(func() {
+		registry.Lock()
+		defer registry.Unlock()
+		delete(registry.actions, action.Name)
+	})
+}
+
+func TestRun(t *testing.T) {
+	var got []string
+	register(t, Action{
+		Name: "test-run",
+		Args: []Arg{{Name: "app", Type: ArgString}},
+		Run: func(args Args) error {
+			got = append(got, args.String("app"))
+			if args.String("app") == "Fail.exe" {
+				return errors.New("failed")
+			}
+			return nil
+		},
+	})
+
+	if err := Run("test", "test-run:Game.exe"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run("test", "test-run Fail.exe"); err == nil {
+		t.Fatal("Expected the error of the action")
+	}
+	if err := Run("test", "missing"); !errors.Is(err, ErrUnknownAction) {
+		t.Fatalf("Expected ErrUnknownAction, got %v", err)
+	}
+	if err := Check("test-run"); err == nil {
+		t.Fatal("Expected a missing argument")
+	}
+	if !reflect.DeepEqual(got, []string{"Game.exe", "Fail.exe"}) {
+		t.Fatalf("Unexpected runs %v", got)
+	}
+}
+
+func TestBuiltinActions(t *testing.T) {
+	for _, name := range []string{"toggle-manage", "apply", "restore", "capture-geometry", "move-to-next-monitor", "launch",
+		"cycle-layout", "select-layout", "workspace", "deactivate-workspace", "toggle-workspace",
+		"send-to-zone", "next-zone", "span-zones", "import-fancyzones"} {
+		if _, ok := Lookup(name); !ok {
+			t.Errorf("Expected %s to be registered", name)
+		}
+	}
+}
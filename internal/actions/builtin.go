@@ -0,0 +1,221 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/skryvvara/focusframe/config"
+	"github.com/skryvvara/focusframe/internal/fancyzones"
+	"github.com/skryvvara/focusframe/process"
+	"github.com/skryvvara/focusframe/window"
+	"github.com/skryvvara/focusframe/zones"
+)
+
+// The actions working on windows and apps, the app registers the ones concerning its user interface itself.
+func init() {
+	Register(Action{
+		Name:        "toggle-manage",
+		Description: "Add the focused app to the managed apps or remove it",
+		Run: func(args Args) error {
+			return window.ToggleForegroundApp()
+		},
+	})
+	Register(Action{
+		Name:        "apply",
+		Description: "Apply the settings of a managed app, the focused one by default",
+		Args:        []Arg{{Name: "executable", Type: ArgString, Optional: true}},
+		Run: func(args Args) error {
+			if args.Has("executable") {
+				return window.Apply(args.String("executable"))
+			}
+			return window.ApplyFocused()
+		},
+	})
+	Register(Action{
+		Name:        "restore",
+		Description: "Restore the original state of the windows of an app, all windows by default",
+		Args:        []Arg{{Name: "executable", Type: ArgString, Optional: true}},
+		Run: func(args Args) error {
+			if args.Has("executable") {
+				window.Restore(args.String("executable"))
+			} else {
+				window.RestoreAll()
+			}
+			return nil
+		},
+	})
+	Register(Action{
+		Name:        "capture-geometry",
+		Description: "Save the current position and size of the focused window as the dimensions of its app",
+		Run: func(args Args) error {
+			_, err := window.CaptureGeometry()
+			return err
+		},
+	})
+	Register(Action{
+		Name:        "move-to-next-monitor",
+		Description: "Move the focused window to the next monitor",
+		Run: func(args Args) error {
+			return window.MoveToNextMonitor()
+		},
+	})
+	Register(Action{
+		Name:        "launch",
+		Description: "Start a managed app with its launch setting or the executable at the given path",
+		Args:        []Arg{{Name: "app", Type: ArgString}},
+		Run: func(args Args) error {
+			return launch(args.String("app"))
+		},
+	})
+	Register(Action{
+		Name:        "cycle-layout",
+		Description: "Switch a managed app to its next layout, the focused one by default",
+		Args:        []Arg{{Name: "executable", Type: ArgString, Optional: true}},
+		Run: func(args Args) error {
+			_, err := window.CycleLayout(args.String("executable"))
+			return err
+		},
+	})
+	Register(Action{
+		Name:        "select-layout",
+		Description: "Switch a managed app to the given layout, the focused one by default",
+		Args:        []Arg{{Name: "layout", Type: ArgString}, {Name: "executable", Type: ArgString, Optional: true}},
+		Run: func(args Args) error {
+			return window.SelectLayout(args.String("executable"), args.String("layout"))
+		},
+	})
+	Register(Action{
+		Name:        "send-to-zone",
+		Description: "Move the focused window into a zone of its monitor, counted from 1",
+		Args:        []Arg{{Name: "zone", Type: ArgInt}},
+		Run: func(args Args) error {
+			return window.SendToZone(args.Int("zone"))
+		},
+	})
+	Register(Action{
+		Name:        "next-zone",
+		Description: "Move the focused window into the zone following the one it is in",
+		Run: func(args Args) error {
+			return window.NextZone()
+		},
+	})
+	Register(Action{
+		Name:        "span-zones",
+		Description: "Move the focused window over a range of zones like 1-2",
+		Args:        []Arg{{Name: "zones", Type: ArgString}},
+		Run: func(args Args) error {
+			first, last, err := zones.ParseRange(args.String("zones"))
+			if err != nil {
+				return err
+			}
+			return window.SpanZones(first, last)
+		},
+	})
+	Register(Action{
+		Name:        "import-fancyzones",
+		Description: "Use the layouts of FancyZones as the zone layouts of the monitors, read from its settings folder by default",
+		Args:        []Arg{{Name: "folder", Type: ArgString, Optional: true}},
+		Run: func(args Args) error {
+			return importFancyZones(args.String("folder"))
+		},
+	})
+	Register(Action{
+		Name:        "workspace",
+		Description: "Arrange the apps of a workspace, launching them if the workspace is configured to",
+		Args:        []Arg{{Name: "name", Type: ArgString}},
+		Run: func(args Args) error {
+			return window.ActivateWorkspace(context.Background(), args.String("name"))
+		},
+	})
+	Register(Action{
+		Name:        "deactivate-workspace",
+		Description: "Restore the arrangement from before the active workspace",
+		Run: func(args Args) error {
+			window.DeactivateWorkspace()
+			return nil
+		},
+	})
+	Register(Action{
+		Name:        "toggle-workspace",
+		Description: "Arrange the apps of a workspace, or restore the arrangement from before if it is active",
+		Args:        []Arg{{Name: "name", Type: ArgString}},
+		Run: func(args Args) error {
+			if window.ActiveWorkspace() == args.String("name") {
+				window.DeactivateWorkspace()
+				return nil
+			}
+			return window.ActivateWorkspace(context.Background(), args.String("name"))
+		},
+	})
+	Register(Action{
+		Name:        "pause",
+		Description: "Stop applying settings to windows, for the given duration or until resumed",
+		Args:        []Arg{{Name: "duration", Type: ArgDuration, Optional: true}},
+		Run: func(args Args) error {
+			window.Pause(args.Duration("duration"))
+			return nil
+		},
+	})
+	Register(Action{
+		Name:        "pause-until-blur",
+		Description: "Stop applying settings to windows until an app loses focus, the last focused managed app by default",
+		Args:        []Arg{{Name: "executable", Type: ArgString, Optional: true}},
+		Run: func(args Args) error {
+			return window.PauseUntilBlur(args.String("executable"))
+		},
+	})
+	Register(Action{
+		Name:        "resume",
+		Description: "Continue applying settings to windows after a pause",
+		Run: func(args Args) error {
+			window.Resume()
+			return nil
+		},
+	})
+	Register(Action{
+		Name:        "toggle-pause",
+		Description: "Pause applying settings to windows until resumed, or resume it",
+		Run: func(args Args) error {
+			window.TogglePause()
+			return nil
+		},
+	})
+}
+
+// launch starts the app. Managed apps are started with their launch setting, anything else is taken for a path.
+func launch(app string) error {
+	path := app
+	if managed, ok := config.GetManagedApp(app); ok && managed.Launch != "" {
+		path = managed.Launch
+	}
+	return process.Launch(path)
+}
+
+// importFancyZones saves the layouts FancyZones applied to the connected monitors as their zone layouts.
+func importFancyZones(dir string) error {
+	if dir == "" {
+		dir = fancyzones.DefaultDir()
+	}
+	sorted, err := window.Monitors()
+	if err != nil {
+		return err
+	}
+	monitors := make([]fancyzones.Monitor, len(sorted))
+	for i, m := range sorted {
+		monitors[i] = fancyzones.Monitor{Number: i + 1, ID: m.ID}
+	}
+
+	result, err := fancyzones.Import(dir, monitors)
+	if err != nil {
+		return err
+	}
+	for _, skipped := range result.Skipped {
+		log.Printf("Skipped FancyZones layout of %s\n", skipped)
+	}
+	if len(result.Layouts) == 0 {
+		return errors.New("found no FancyZones layouts for the connected monitors")
+	}
+	log.Printf("Importing %d FancyZones layouts\n", len(result.Layouts))
+	return config.SetZoneLayouts(result.Layouts)
+}
@@ -0,0 +1,240 @@
+// Package fancyzones imports the layouts applied in PowerToys FancyZones as zone layouts of the config.
+package fancyzones
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+const (
+	// CustomLayoutsFile holds the layouts created in the FancyZones editor.
+	CustomLayoutsFile = "custom-layouts.json"
+	// AppliedLayoutsFile holds the layout of every monitor and virtual desktop.
+	AppliedLayoutsFile = "applied-layouts.json"
+)
+
+// Monitor is a monitor layouts can be imported for.
+type Monitor struct {
+	Number int    // Counted from the left starting at 1, like in the config
+	ID     string // Display hardware and instance like "DELA026#5&10a58c63&0&UID16777488", empty if unknown
+}
+
+// Result holds the imported zone layouts and what could not be imported.
+type Result struct {
+	Layouts []config.ZoneLayout
+	Skipped []string // Why applied layouts were not imported
+}
+
+type customLayouts struct {
+	CustomLayouts []customLayout `json:"custom-layouts"`
+}
+
+type customLayout struct {
+	UUID string          `json:"uuid"`
+	Name string          `json:"name"`
+	Type string          `json:"type"` // canvas or grid
+	Info json.RawMessage `json:"info"`
+}
+
+type canvasInfo struct {
+	RefWidth  int `json:"ref-width"`
+	RefHeight int `json:"ref-height"`
+	Zones     []struct {
+		X      int `json:"X"`
+		Y      int `json:"Y"`
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	} `json:"zones"`
+}
+
+type gridInfo struct {
+	RowsPercentage    []int   `json:"rows-percentage"`
+	ColumnsPercentage []int   `json:"columns-percentage"`
+	CellChildMap      [][]int `json:"cell-child-map"` // Zone of every cell, counted from 0
+}
+
+type appliedLayouts struct {
+	AppliedLayouts []appliedLayout `json:"applied-layouts"`
+}
+
+type appliedLayout struct {
+	Device struct {
+		Monitor         string `json:"monitor"`
+		MonitorInstance string `json:"monitor-instance"`
+		MonitorNumber   int    `json:"monitor-number"`
+	} `json:"device"`
+	AppliedLayout struct {
+		UUID        string `json:"uuid"`
+		Type        string `json:"type"` // custom for custom layouts, otherwise the name of a template
+		ShowSpacing bool   `json:"show-spacing"`
+		Spacing     int    `json:"spacing"`
+		ZoneCount   int    `json:"zone-count"`
+	} `json:"applied-layout"`
+}
+
+// DefaultDir returns the directory FancyZones keeps its layouts in.
+func DefaultDir() string {
+	return filepath.Join(os.Getenv("LOCALAPPDATA"), "Microsoft", "PowerToys", "FancyZones")
+}
+
+// Import reads the layouts FancyZones applied to the monitors from its files in dir, see Convert.
+//
+// Returns either the result or an error if a file could not be read or parsed.
+func Import(dir string, monitors []Monitor) (Result, error) {
+	custom, err := os.ReadFile(filepath.Join(dir, CustomLayoutsFile))
+	if err != nil && !os.IsNotExist(err) {
+		return Result{}, err
+	}
+	applied, err := os.ReadFile(filepath.Join(dir, AppliedLayoutsFile))
+	if err != nil {
+		return Result{}, err
+	}
+	return Convert(custom, applied, monitors)
+}
+
+// Convert turns the layouts FancyZones applied to the given monitors into zone layouts. Custom canvas and grid layouts
+// are supported as well as the columns and rows templates.
+//
+// Monitors are matched by their ID. If none of the monitors has an ID, the monitor number of FancyZones is used
+// instead, which is the number Windows gives the display and may not be counted from the left. Only the first layout of
+// a monitor is imported if FancyZones has one for every virtual desktop. Layouts which can't be imported, e.g. for
+// disconnected monitors, are listed in Result.Skipped.
+//
+// Returns either the result or an error if the files could not be parsed.
+func Convert(custom []byte, applied []byte, monitors []Monitor) (Result, error) {
+	var customs customLayouts
+	if len(custom) > 0 {
+		if err := json.Unmarshal(custom, &customs); err != nil {
+			return Result{}, fmt.Errorf("could not parse %s: %w", CustomLayoutsFile, err)
+		}
+	}
+	var applieds appliedLayouts
+	if err := json.Unmarshal(applied, &applieds); err != nil {
+		return Result{}, fmt.Errorf("could not parse %s: %w", AppliedLayoutsFile, err)
+	}
+
+	result := Result{}
+	imported := map[int]bool{}
+	for _, a := range applieds.AppliedLayouts {
+		device := a.device()
+		number := match(a, monitors)
+		if number == 0 {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: the monitor is not connected", device))
+			continue
+		}
+		if imported[number] {
+			continue
+		}
+
+		z, err := convert(a, customs.CustomLayouts)
+		if err == nil {
+			z.Monitor = number
+			err = z.Validate()
+		}
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %v", device, err))
+			continue
+		}
+		result.Layouts = append(result.Layouts, z)
+		imported[number] = true
+	}
+	return result, nil
+}
+
+// device returns the ID of the monitor the layout was applied to in the format of Monitor.ID. The instance is left out
+// if FancyZones doesn't know it.
+func (a appliedLayout) device() string {
+	if a.Device.MonitorInstance == "" {
+		return a.Device.Monitor
+	}
+	return a.Device.Monitor + "#" + a.Device.MonitorInstance
+}
+
+// match returns the number of the monitor the layout was applied to, or 0 if it isn't one of the monitors.
+func match(a appliedLayout, monitors []Monitor) int {
+	known := false
+	for _, m := range monitors {
+		if m.ID == "" {
+			continue
+		}
+		known = true
+		if strings.EqualFold(m.ID, a.device()) {
+			return m.Number
+		}
+	}
+	if known {
+		return 0
+	}
+	for _, m := range monitors {
+		if m.Number == a.Device.MonitorNumber {
+			return m.Number
+		}
+	}
+	return 0
+}
+
+// convert returns the zone layout of an applied layout without its monitor.
+func convert(a appliedLayout, customs []customLayout) (config.ZoneLayout, error) {
+	spacing := 0
+	if a.AppliedLayout.ShowSpacing {
+		spacing = a.AppliedLayout.Spacing
+	}
+
+	switch a.AppliedLayout.Type {
+	case "columns":
+		return config.ZoneLayout{Type: "columns", Columns: a.AppliedLayout.ZoneCount, Spacing: spacing}, nil
+	case "rows":
+		return config.ZoneLayout{Type: "rows", Rows: a.AppliedLayout.ZoneCount, Spacing: spacing}, nil
+	case "custom":
+	default:
+		return config.ZoneLayout{}, fmt.Errorf("the %s template is not supported", a.AppliedLayout.Type)
+	}
+
+	i := 0
+	for i < len(customs) && !strings.EqualFold(customs[i].UUID, a.AppliedLayout.UUID) {
+		i++
+	}
+	if i == len(customs) {
+		return config.ZoneLayout{}, fmt.Errorf("the custom layout %s does not exist", a.AppliedLayout.UUID)
+	}
+	custom := customs[i]
+
+	switch custom.Type {
+	case "canvas":
+		var info canvasInfo
+		if err := json.Unmarshal(custom.Info, &info); err != nil {
+			return config.ZoneLayout{}, fmt.Errorf("invalid canvas layout %q: %w", custom.Name, err)
+		}
+		z := config.ZoneLayout{Type: "canvas", ReferenceWidth: info.RefWidth, ReferenceHeight: info.RefHeight}
+		for _, zone := range info.Zones {
+			z.Canvas = append(z.Canvas, config.ZoneRect{X: zone.X, Y: zone.Y, Width: zone.Width, Height: zone.Height})
+		}
+		return z, nil
+	case "grid":
+		var info gridInfo
+		if err := json.Unmarshal(custom.Info, &info); err != nil {
+			return config.ZoneLayout{}, fmt.Errorf("invalid grid layout %q: %w", custom.Name, err)
+		}
+		z := config.ZoneLayout{
+			Type:         "grid",
+			RowHeights:   info.RowsPercentage,
+			ColumnWidths: info.ColumnsPercentage,
+			Spacing:      spacing,
+		}
+		for _, row := range info.CellChildMap {
+			cells := make([]int, len(row))
+			for c, zone := range row {
+				cells[c] = zone + 1
+			}
+			z.Cells = append(z.Cells, cells)
+		}
+		return z, nil
+	default:
+		return config.ZoneLayout{}, fmt.Errorf("custom layout %q has the unsupported type %s", custom.Name, custom.Type)
+	}
+}
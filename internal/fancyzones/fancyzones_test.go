@@ -0,0 +1,95 @@
+package fancyzones
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+var (
+	grid = config.ZoneLayout{
+		Type:         "grid",
+		RowHeights:   []int{5000, 5000},
+		ColumnWidths: []int{2500, 5000, 2500},
+		Cells:        [][]int{{1, 2, 3}, {1, 2, 4}},
+		Spacing:      16,
+	}
+	canvas = config.ZoneLayout{
+		Type:            "canvas",
+		ReferenceWidth:  2560,
+		ReferenceHeight: 1400,
+		Canvas: []config.ZoneRect{
+			{Width: 1920, Height: 1080}, {X: 1920, Width: 640, Height: 1400}, {X: 1600, Y: 800, Width: 640, Height: 360},
+		},
+	}
+)
+
+func TestImport(t *testing.T) {
+	result, err := Import("testdata", []Monitor{
+		{Number: 1, ID: "GSM5B7F#5&10a58c63&0&UID16777489"},
+		{Number: 2, ID: "dela026#5&10a58c63&0&UID16777488"},
+		// FancyZones doesn't know the instance of every monitor
+		{Number: 3, ID: "SAM0F00"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	canvas, grid := canvas, grid
+	canvas.Monitor, grid.Monitor = 1, 2
+	columns := config.ZoneLayout{Monitor: 3, Type: "columns", Columns: 2}
+	// the columns of the second virtual desktop of monitor 2 are ignored
+	if want := []config.ZoneLayout{grid, canvas, columns}; !reflect.DeepEqual(result.Layouts, want) {
+		t.Fatalf("Expected %+v, got %+v", want, result.Layouts)
+	}
+	// the priority grid is for a disconnected monitor
+	if len(result.Skipped) != 1 {
+		t.Fatalf("Expected one skipped layout, got %v", result.Skipped)
+	}
+}
+
+func TestImportByMonitorNumber(t *testing.T) {
+	result, err := Import("testdata", []Monitor{{Number: 1}, {Number: 2}, {Number: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	canvas, grid := canvas, grid
+	canvas.Monitor, grid.Monitor = 1, 2
+	if want := []config.ZoneLayout{grid, canvas}; !reflect.DeepEqual(result.Layouts, want) {
+		t.Fatalf("Expected %+v, got %+v", want, result.Layouts)
+	}
+	// the priority grid template is not supported and the columns are for a disconnected monitor
+	if len(result.Skipped) != 2 {
+		t.Fatalf("Expected two skipped layouts, got %v", result.Skipped)
+	}
+}
+
+func TestConvertInvalid(t *testing.T) {
+	monitors := []Monitor{{Number: 1}}
+	applied := []byte(`{"applied-layouts": [{"device": {"monitor-number": 1}, "applied-layout": {"uuid": "{A}", "type": "custom"}}]}`)
+
+	if _, err := Convert(nil, []byte("{"), monitors); err == nil {
+		t.Fatal("Expected an error for malformed applied layouts")
+	}
+
+	result, err := Convert(nil, applied, monitors)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Layouts) != 0 || len(result.Skipped) != 1 {
+		t.Fatalf("Expected a missing custom layout to be skipped, got %+v", result)
+	}
+
+	// zone 2 covers two cells which don't form a rectangle
+	custom := []byte(`{"custom-layouts": [{"uuid": "{A}", "name": "Broken", "type": "grid", "info": {
+		"rows-percentage": [5000, 5000], "columns-percentage": [5000, 5000], "cell-child-map": [[0, 1], [1, 2]]}}]}`)
+	result, err = Convert(custom, applied, monitors)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Layouts) != 0 || len(result.Skipped) != 1 {
+		t.Fatalf("Expected an invalid grid to be skipped, got %+v", result)
+	}
+}
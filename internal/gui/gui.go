@@ -6,8 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/skryvvara/focusframe/config"
+	"github.com/skryvvara/focusframe/internal/actions"
+	"github.com/skryvvara/focusframe/window"
 	webview "github.com/webview/webview_go"
 )
 
@@ -36,8 +39,11 @@ func ShowGUI() {
 	})
 
 	w.Bind("getManagedApps", bindGetManagedApps)
+	w.Bind("getProblems", bindGetProblems)
+	w.Bind("getActivity", bindGetActivity)
 	w.Bind("saveGlobalConfigChanges", bindSaveGlobalConfigChanges)
 	w.Bind("saveAppChanges", bindSaveAppChanges)
+	w.Bind("runAction", bindRunAction)
 
 	w.SetTitle(fmt.Sprintf("FocusFrame %s", config.Version))
 	w.SetSize(600, 600, webview.HintFixed)
@@ -48,13 +54,64 @@ func ShowGUI() {
 
 // bindGetManagedApps returns the list of managed applications as a JSON string.
 func bindGetManagedApps() string {
+	config.ManagedAppsLock.Lock()
 	data, err := json.Marshal(config.Config.ManagedApps)
+	config.ManagedAppsLock.Unlock()
 	if err != nil {
 		panic(err)
 	}
 	return string(data)
 }
 
+// bindGetProblems returns the managed apps FocusFrame can't change and what to do about it as a JSON string.
+func bindGetProblems() string {
+	type problem struct {
+		Executable string
+		Advice     string
+	}
+
+	problems := []problem{}
+	for _, p := range window.Problems() {
+		problems = append(problems, problem{Executable: p.Executable, Advice: p.Advice()})
+	}
+
+	data, err := json.Marshal(problems)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+// bindGetActivity returns the history of what FocusFrame did on its own as a JSON string, the latest activity first.
+func bindGetActivity() string {
+	type activity struct {
+		Time       string
+		Executable string
+		Message    string
+	}
+
+	history := window.Activities()
+	list := make([]activity, 0, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		a := history[i]
+		list = append(list, activity{Time: a.Time.Format("15:04:05"), Executable: a.Executable, Message: a.Message})
+	}
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+// bindRunAction runs the action invocation and returns its error message or "" on success.
+func bindRunAction(invocation string) string {
+	if err := actions.Run("gui", invocation); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
 // bindSaveGlobalConfigChanges updates the global configuration using data passed from the GUI.
 func bindSaveGlobalConfigChanges(data map[string]interface{}) {
 	cfg := &config.Config.Global
@@ -71,7 +128,7 @@ func bindSaveGlobalConfigChanges(data map[string]interface{}) {
 		cfg.OffsetY = int(v)
 	}
 	if v, ok := data["Delay"].(float64); ok {
-		cfg.Delay = int(v)
+		cfg.Delay = config.Duration(time.Duration(v * float64(time.Second)))
 	}
 	if v, ok := data["Hotkey"].(float64); ok {
 		cfg.Hotkey = int(v)
@@ -111,11 +168,28 @@ func bindSaveAppChanges(data map[string]interface{}) {
 		return
 	}
 
-	if _, ok := config.Config.ManagedApps[newAppSettings.Executable]; !ok {
+	config.ManagedAppsLock.Lock()
+	defer config.ManagedAppsLock.Unlock()
+	existing, ok := config.Config.ManagedApps[newAppSettings.Executable]
+	if !ok {
 		log.Println("Failed to update")
 		return
 	}
 
+	// keep the settings which can only be changed in the config file
+	newAppSettings.Ready = existing.Ready
+	newAppSettings.Enforce = existing.Enforce
+	newAppSettings.Style = existing.Style
+	newAppSettings.State = existing.State
+	newAppSettings.ApplyOn = existing.ApplyOn
+	newAppSettings.Launch = existing.Launch
+	newAppSettings.Layouts = existing.Layouts
+	newAppSettings.ActiveLayout = existing.ActiveLayout
+	newAppSettings.Companions = existing.Companions
+	newAppSettings.Focus = existing.Focus
+	newAppSettings.GuardFocus = existing.GuardFocus
+	newAppSettings.Dimensions.SizeMode = existing.Dimensions.SizeMode
+
 	config.Config.ManagedApps[newAppSettings.Executable] = newAppSettings
 	err = config.SaveConfig()
 	if err != nil {
@@ -0,0 +1,159 @@
+package ipc
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// endpointFile is the file in the config directory the running instance writes its port and token to.
+const endpointFile = "ipc.json"
+
+// timeout limits how long a single request may take, actions run synchronously and should be quick.
+const timeout = 30 * time.Second
+
+// endpoint tells clients where the running instance listens. The token keeps other users on the same machine, which
+// can't read the file, from running actions.
+type endpoint struct {
+	Port  int    `json:"port"`
+	Token string `json:"token"`
+}
+
+// Request asks the running instance to run an action.
+type Request struct {
+	Token      string `json:"token"`
+	Source     string `json:"source"` // e.g. "cli", used for logging
+	Invocation string `json:"invocation"`
+}
+
+// Response reports the result of a Request, Error is empty on success.
+type Response struct {
+	Error string `json:"error,omitempty"`
+}
+
+// Handler runs the invocation of a request.
+type Handler func(source string, invocation string) error
+
+// Serve accepts requests on a localhost port until the context is cancelled. The port and a random token are written
+// to a file in dir, which is removed again when Serve returns.
+//
+// Returns an error if the port could not be opened or the file could not be written.
+func Serve(ctx context.Context, dir string, handler Handler) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, endpointFile)
+	data, err := json.Marshal(endpoint{Port: listener.Addr().(*net.TCPAddr).Port, Token: token})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go handle(conn, token, handler)
+	}
+}
+
+// handle answers a single request.
+func handle(conn net.Conn, token string, handler Handler) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	var req Request
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		log.Println("Invalid IPC request:", err)
+		return
+	}
+
+	var resp Response
+	// compare in constant time, so the token can't be guessed from how long the answer takes
+	if subtle.ConstantTimeCompare([]byte(req.Token), []byte(token)) != 1 {
+		log.Println("Rejected IPC request with an invalid token")
+		resp.Error = "invalid token"
+	} else if err := handler(req.Source, req.Invocation); err != nil {
+		resp.Error = err.Error()
+	}
+	if err := json.NewEncoder(conn).Encode(resp); err != nil {
+		log.Println("Error answering IPC request:", err)
+	}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ErrNotRunning is returned by Send if no instance is serving requests.
+var ErrNotRunning = errors.New("FocusFrame is not running")
+
+// Send asks the instance serving requests in dir to run the invocation.
+//
+// Returns either nil if the action ran successfully, ErrNotRunning or the error of the action.
+func Send(dir string, source string, invocation string) error {
+	data, err := os.ReadFile(filepath.Join(dir, endpointFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotRunning
+	}
+	if err != nil {
+		return err
+	}
+	var ep endpoint
+	if err := json.Unmarshal(data, &ep); err != nil {
+		return fmt.Errorf("invalid %s: %v", endpointFile, err)
+	}
+
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", ep.Port), time.Second)
+	if err != nil {
+		// the file is left behind if the instance crashed
+		return fmt.Errorf("%w: %v", ErrNotRunning, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	if err := json.NewEncoder(conn).Encode(Request{Token: ep.Token, Source: source, Invocation: invocation}); err != nil {
+		return err
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
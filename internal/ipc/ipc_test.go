@@ -0,0 +1,92 @@
+package ipc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// serve runs a server in dir for the duration of the test and waits until it is ready.
+func serve(t *testing.T, dir string, handler Handler) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Serve(ctx, dir, handler) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := os.Stat(filepath.Join(dir, endpointFile)); err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Server did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSend(t *testing.T) {
+	dir := t.TempDir()
+	got := make(chan string, 2)
+	serve(t, dir, func(source string, invocation string) error {
+		got <- source + ": " + invocation
+		if invocation == "fail" {
+			return errors.New("action failed")
+		}
+		return nil
+	})
+
+	if err := Send(dir, "cli", "apply Game.exe"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Send(dir, "cli", "fail"); err == nil || err.Error() != "action failed" {
+		t.Fatalf("Expected the error of the action, got %v", err)
+	}
+	if first := <-got; first != "cli: apply Game.exe" {
+		t.Fatalf("Unexpected request %q", first)
+	}
+}
+
+func TestSendInvalidToken(t *testing.T) {
+	dir := t.TempDir()
+	serve(t, dir, func(source string, invocation string) error {
+		t.Error("Expected the request to be rejected")
+		return nil
+	})
+
+	path := filepath.Join(dir, endpointFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// keep the port but replace the token
+	var ep endpoint
+	if err := json.Unmarshal(data, &ep); err != nil {
+		t.Fatal(err)
+	}
+	ep.Token = "forged"
+	if data, err = json.Marshal(ep); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Send(dir, "cli", "apply"); err == nil {
+		t.Fatal("Expected the request to be rejected")
+	}
+}
+
+func TestSendNotRunning(t *testing.T) {
+	if err := Send(t.TempDir(), "cli", "apply"); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("Expected ErrNotRunning, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package startup
 import (
 	"os"
 
+	"golang.org/x/sys/windows"
 	"golang.org/x/sys/windows/registry"
 )
 
@@ -67,3 +68,24 @@ func IsEnabled() (bool, error) {
 
 	return val == `"`+exePath+`"`, nil
 }
+
+// RestartElevated starts a new instance of the app as administrator, the user is asked for permission by UAC. The
+// caller is expected to quit afterwards.
+//
+// Returns an error if the instance could not be started, e.g. because the user declined.
+func RestartElevated() error {
+	exePath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	verb, err := windows.UTF16PtrFromString("runas")
+	if err != nil {
+		return err
+	}
+	file, err := windows.UTF16PtrFromString(exePath)
+	if err != nil {
+		return err
+	}
+	return windows.ShellExecute(0, verb, file, nil, nil, windows.SW_NORMAL)
+}
@@ -0,0 +1,21 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Elevated reports whether FocusFrame runs as root.
+func Elevated() bool {
+	return os.Geteuid() == 0
+}
+
+// elevationOf reports whether FocusFrame and the process with the given PID run as root.
+func elevationOf(pid uint32) (self bool, target bool, err error) {
+	uid, err := readUID(filepath.Join(proc.root, strconv.FormatUint(uint64(pid), 10)))
+	if err != nil {
+		return false, false, err
+	}
+	return Elevated(), uid == "0", nil
+}
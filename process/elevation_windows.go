@@ -0,0 +1,30 @@
+package process
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// Elevated reports whether FocusFrame runs as administrator.
+func Elevated() bool {
+	return windows.GetCurrentProcessToken().IsElevated()
+}
+
+// elevationOf reports whether FocusFrame and the process with the given PID run elevated. The token of an elevated
+// process can still be queried with limited access rights, which is all that is required.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winnt/ne-winnt-token_information_class
+func elevationOf(pid uint32) (self bool, target bool, err error) {
+	hProcess, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return false, false, err
+	}
+	defer windows.CloseHandle(hProcess)
+
+	var token windows.Token
+	if err := windows.OpenProcessToken(hProcess, windows.TOKEN_QUERY, &token); err != nil {
+		return false, false, err
+	}
+	defer token.Close()
+
+	return Elevated(), token.IsElevated(), nil
+}
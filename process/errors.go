@@ -0,0 +1,63 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	// ErrProcessNotFound is returned if no process with the given PID or executable is running.
+	ErrProcessNotFound = errors.New("process not found")
+	// ErrAccessDenied is returned if FocusFrame lacks the rights to query or change a process or its windows.
+	ErrAccessDenied = errors.New("access denied")
+	// ErrTargetElevated is returned if access was denied because the process runs as administrator and FocusFrame
+	// doesn't. It wraps ErrAccessDenied, so errors.Is matches both.
+	ErrTargetElevated = fmt.Errorf("%w, the process runs as administrator", ErrAccessDenied)
+)
+
+// Error describes a failed operation on a process. Err is one of the errors above or the error of the underlying
+// system call.
+type Error struct {
+	Op   string // e.g. "open" or "move window"
+	PID  uint32 // 0 if the process was looked up by name
+	Name string // name of the executable, if known
+	Err  error
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Name != "" && e.PID != 0:
+		return fmt.Sprintf("%s %s (%d): %v", e.Op, e.Name, e.PID, e.Err)
+	case e.Name != "":
+		return fmt.Sprintf("%s %s: %v", e.Op, e.Name, e.Err)
+	}
+	return fmt.Sprintf("%s process %d: %v", e.Op, e.PID, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// elevation reports whether FocusFrame and the process with the given PID run elevated, tests replace it.
+var elevation = elevationOf
+
+// AccessError classifies the error of an operation on the process with the given PID or one of its windows. A denied
+// access is reported as ErrTargetElevated if the process runs elevated and FocusFrame doesn't, otherwise as
+// ErrAccessDenied.
+//
+// Returns nil for a nil error and an *Error wrapping any other error as it is.
+func AccessError(op string, pid uint32, err error) error {
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, os.ErrPermission) {
+		return &Error{Op: op, PID: pid, Err: err}
+	}
+
+	self, target, elevationErr := elevation(pid)
+	if elevationErr == nil && target && !self {
+		return &Error{Op: op, PID: pid, Err: ErrTargetElevated}
+	}
+	return &Error{Op: op, PID: pid, Err: ErrAccessDenied}
+}
@@ -0,0 +1,61 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// useElevation replaces the elevation check for the duration of the test.
+func useElevation(t *testing.T, self bool, target bool) {
+	t.Helper()
+	previous := elevation
+	elevation = func(pid uint32) (bool, bool, error) { return self, target, nil }
+	t.Cleanup(func() { elevation = previous })
+}
+
+func TestAccessError(t *testing.T) {
+	denied := fmt.Errorf("SetWindowPos: %w", os.ErrPermission)
+
+	useElevation(t, false, true)
+	err := AccessError("move window of", 42, denied)
+	if !errors.Is(err, ErrTargetElevated) || !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("Expected an elevated target, got %v", err)
+	}
+	var processErr *Error
+	if !errors.As(err, &processErr) || processErr.PID != 42 {
+		t.Fatalf("Expected an *Error for process 42, got %#v", err)
+	}
+
+	// an elevated FocusFrame is denied for other reasons, e.g. protected processes
+	useElevation(t, true, true)
+	if err := AccessError("open", 42, denied); errors.Is(err, ErrTargetElevated) || !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("Expected access denied only, got %v", err)
+	}
+
+	other := errors.New("invalid handle")
+	if err := AccessError("open", 42, other); !errors.Is(err, other) || errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("Expected the error to be kept, got %v", err)
+	}
+	if err := AccessError("open", 42, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		err  *Error
+		want string
+	}{
+		{&Error{Op: "open", PID: 42, Err: ErrAccessDenied}, "open process 42: access denied"},
+		{&Error{Op: "find", Name: "Game.exe", Err: ErrProcessNotFound}, "find Game.exe: process not found"},
+		{&Error{Op: "move window of", PID: 42, Name: "Game.exe", Err: ErrTargetElevated}, "move window of Game.exe (42): access denied, the process runs as administrator"},
+	}
+
+	for _, test := range tests {
+		if got := test.err.Error(); got != test.want {
+			t.Errorf("Expected %q, got %q", test.want, got)
+		}
+	}
+}
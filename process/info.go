@@ -0,0 +1,54 @@
+package process
+
+import (
+	"strings"
+	"time"
+)
+
+// Info describes a single running process.
+//
+// Fields that could not be resolved (e.g. because of missing permissions) are left at their zero value.
+type Info struct {
+	PID       uint32
+	PPID      uint32
+	Exe       string // Full path of the executable, e.g. C:/Applications/Program.exe
+	Name      string // Name of the executable, e.g. Program.exe
+	CmdLine   string
+	StartTime time.Time
+	User      string
+	SessionID uint32
+}
+
+// List returns information about all running processes.
+//
+// Returns either the list of processes or an error if the process table could not be read.
+func List() ([]Info, error) {
+	return list()
+}
+
+// Get returns information about the process with the given PID (Process ID).
+//
+// Returns either the Info of the process or an error if no such process exists.
+func Get(pid uint32) (Info, error) {
+	return get(pid)
+}
+
+// FindByName returns the PIDs of all running processes with the given executable name, e.g. Program.exe. Names are
+// compared case-insensitively.
+//
+// Returns either the list of PIDs (which is empty if no process matched) or an error if the process table could not
+// be read.
+func FindByName(name string) ([]uint32, error) {
+	processes, err := scan()
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []uint32
+	for _, info := range processes {
+		if strings.EqualFold(info.Name, name) {
+			pids = append(pids, info.PID)
+		}
+	}
+	return pids, nil
+}
@@ -0,0 +1,259 @@
+package process
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// procFS reads process information from a /proc tree.
+type procFS struct {
+	root string
+	// clockTicks is the number of clock ticks per second (USER_HZ) used for the starttime field in stat.
+	clockTicks int64
+	// lookupUser resolves a numeric uid to a user name.
+	lookupUser func(uid string) string
+}
+
+var proc = procFS{
+	root:       "/proc",
+	clockTicks: 100,
+	lookupUser: func(uid string) string {
+		if u, err := user.LookupId(uid); err == nil {
+			return u.Username
+		}
+		return uid
+	},
+}
+
+func list() ([]Info, error) {
+	return proc.list()
+}
+
+func get(pid uint32) (Info, error) {
+	return proc.get(pid)
+}
+
+// pids returns the PIDs of all processes found in the /proc tree.
+func (p procFS) pids() ([]uint32, error) {
+	entries, err := os.ReadDir(p.root)
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []uint32
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pid, err := strconv.ParseUint(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		pids = append(pids, uint32(pid))
+	}
+	return pids, nil
+}
+
+// list reads the Info of every process in the /proc tree. Processes that exit while the tree is being read are
+// skipped.
+func (p procFS) list() ([]Info, error) {
+	pids, err := p.pids()
+	if err != nil {
+		return nil, err
+	}
+
+	bootTime, err := p.bootTime()
+	if err != nil {
+		return nil, err
+	}
+
+	processes := make([]Info, 0, len(pids))
+	for _, pid := range pids {
+		info, err := p.read(pid, bootTime)
+		if err != nil {
+			continue
+		}
+		processes = append(processes, info)
+	}
+	return processes, nil
+}
+
+// get reads the Info of a single process from the /proc tree.
+func (p procFS) get(pid uint32) (Info, error) {
+	bootTime, err := p.bootTime()
+	if err != nil {
+		return Info{}, err
+	}
+	return p.read(pid, bootTime)
+}
+
+// read collects all available information about the process with the given PID. Only the stat file is required,
+// everything else is best effort since it might not be readable for processes of other users.
+func (p procFS) read(pid uint32, bootTime time.Time) (Info, error) {
+	dir := filepath.Join(p.root, strconv.FormatUint(uint64(pid), 10))
+
+	st, err := p.readStat(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Info{}, &Error{Op: "get", PID: pid, Err: ErrProcessNotFound}
+	}
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to read stat of process %d: %v", pid, err)
+	}
+
+	info := Info{
+		PID:       pid,
+		PPID:      st.ppid,
+		Name:      st.comm,
+		SessionID: st.session,
+		StartTime: bootTime.Add(time.Duration(st.startTicks) * time.Second / time.Duration(p.clockTicks)),
+	}
+
+	if exe, err := os.Readlink(filepath.Join(dir, "exe")); err == nil {
+		info.Exe = strings.TrimSuffix(exe, " (deleted)")
+		info.Name = filepath.Base(info.Exe)
+	}
+
+	if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		info.CmdLine = strings.TrimSpace(strings.ReplaceAll(string(cmdline), "\x00", " "))
+	}
+
+	if uid, err := readUID(dir); err == nil {
+		info.User = p.lookupUser(uid)
+	}
+
+	return info, nil
+}
+
+// stat holds the fields of /proc/<pid>/stat we are interested in.
+type stat struct {
+	comm       string
+	ppid       uint32
+	session    uint32
+	startTicks uint64
+}
+
+// readStat parses /proc/<pid>/stat. The comm field is wrapped in parentheses and may itself contain spaces and
+// parentheses, so the remaining fields are split after the last closing parenthesis.
+//
+// See https://man7.org/linux/man-pages/man5/proc_pid_stat.5.html
+func (p procFS) readStat(dir string) (stat, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return stat{}, err
+	}
+
+	content := string(data)
+	open := strings.IndexByte(content, '(')
+	end := strings.LastIndexByte(content, ')')
+	if open < 0 || end < open {
+		return stat{}, fmt.Errorf("malformed stat: %q", content)
+	}
+
+	// fields[0] is the state (field 3 in the man page)
+	fields := strings.Fields(content[end+1:])
+	if len(fields) < 20 {
+		return stat{}, fmt.Errorf("malformed stat: %q", content)
+	}
+
+	ppid, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return stat{}, err
+	}
+	session, err := strconv.ParseUint(fields[3], 10, 32)
+	if err != nil {
+		return stat{}, err
+	}
+	startTicks, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return stat{}, err
+	}
+
+	return stat{
+		comm:       content[open+1 : end],
+		ppid:       uint32(ppid),
+		session:    uint32(session),
+		startTicks: startTicks,
+	}, nil
+}
+
+// bootTime reads the system boot time from /proc/stat which is needed to convert process start times.
+func (p procFS) bootTime() (time.Time, error) {
+	file, err := os.Open(filepath.Join(p.root, "stat"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "btime" {
+			seconds, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(seconds, 0), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return time.Time{}, err
+	}
+	return time.Time{}, fmt.Errorf("btime not found in %s", file.Name())
+}
+
+// readUID returns the real uid of the process from /proc/<pid>/status.
+func readUID(dir string) (string, error) {
+	file, err := os.Open(filepath.Join(dir, "status"))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "Uid:" {
+			return fields[1], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("uid not found in %s", file.Name())
+}
+
+func scan() ([]Info, error) {
+	return proc.scan()
+}
+
+// scan only reads the stat file of every process, which is a lot cheaper than a full list.
+func (p procFS) scan() ([]Info, error) {
+	pids, err := p.pids()
+	if err != nil {
+		return nil, err
+	}
+
+	processes := make([]Info, 0, len(pids))
+	for _, pid := range pids {
+		st, err := p.readStat(filepath.Join(p.root, strconv.FormatUint(uint64(pid), 10)))
+		if err != nil {
+			continue
+		}
+		processes = append(processes, Info{
+			PID:  pid,
+			PPID: st.ppid,
+			Name: st.comm,
+			// the raw tick count is enough to tell processes apart, so the boot time isn't read here
+			StartTime: time.Unix(0, int64(st.startTicks)),
+		})
+	}
+	return processes, nil
+}
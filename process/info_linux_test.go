@@ -0,0 +1,139 @@
+package process
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeProc creates a minimal /proc tree in a temporary directory and returns a procFS reading from it.
+func fakeProc(t *testing.T) procFS {
+	root := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "stat"), "cpu  1 2 3 4\nbtime 1700000000\nprocesses 42\n")
+
+	// a process with a comm containing spaces and parentheses
+	dir := filepath.Join(root, "1234")
+	writeFile(t, filepath.Join(dir, "stat"), "1234 (Game (x64) v2) S 1000 1234 1234 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 500 0 0 0 0 0 0")
+	writeFile(t, filepath.Join(dir, "cmdline"), "/opt/game/game.bin\x00--windowed\x00")
+	writeFile(t, filepath.Join(dir, "status"), "Name:\tgame.bin\nUid:\t1000\t1000\t1000\t1000\n")
+	if err := os.Symlink("/opt/game/game.bin", filepath.Join(dir, "exe")); err != nil {
+		t.Fatalf("Failed to create exe link: %v", err)
+	}
+
+	// a process whose exe, cmdline and status are not readable
+	writeFile(t, filepath.Join(root, "1000", "stat"), "1000 (launcher) S 1 1000 1000 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 100 0 0 0 0 0 0")
+
+	// entries that must be ignored
+	writeFile(t, filepath.Join(root, "self"), "")
+	if err := os.MkdirAll(filepath.Join(root, "sys"), os.ModePerm); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+
+	return procFS{
+		root:       root,
+		clockTicks: 100,
+		lookupUser: func(uid string) string { return "user" + uid },
+	}
+}
+
+func writeFile(t *testing.T, name string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+		t.Fatalf("Failed to create dir for '%s': %v", name, err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write '%s': %v", name, err)
+	}
+}
+
+func TestProcFSGet(t *testing.T) {
+	p := fakeProc(t)
+
+	info, err := p.get(1234)
+	if err != nil {
+		t.Fatalf("Failed to get process: %v", err)
+	}
+
+	want := Info{
+		PID:       1234,
+		PPID:      1000,
+		Exe:       "/opt/game/game.bin",
+		Name:      "game.bin",
+		CmdLine:   "/opt/game/game.bin --windowed",
+		StartTime: time.Unix(1700000005, 0),
+		User:      "user1000",
+		SessionID: 1234,
+	}
+	if info != want {
+		t.Fatalf("Unexpected info:\n got: %+v\nwant: %+v", info, want)
+	}
+}
+
+func TestProcFSGetPartial(t *testing.T) {
+	p := fakeProc(t)
+
+	info, err := p.get(1000)
+	if err != nil {
+		t.Fatalf("Failed to get process: %v", err)
+	}
+	if info.Name != "launcher" || info.Exe != "" || info.User != "" || info.PPID != 1 {
+		t.Fatalf("Unexpected info for unreadable process: %+v", info)
+	}
+}
+
+func TestProcFSGetMissing(t *testing.T) {
+	p := fakeProc(t)
+
+	if _, err := p.get(4321); !errors.Is(err, ErrProcessNotFound) {
+		t.Fatalf("Expected ErrProcessNotFound for a missing process, got %v", err)
+	}
+}
+
+func TestProcFSList(t *testing.T) {
+	p := fakeProc(t)
+
+	processes, err := p.list()
+	if err != nil {
+		t.Fatalf("Failed to list processes: %v", err)
+	}
+	if len(processes) != 2 {
+		t.Fatalf("Expected 2 processes, got %d: %+v", len(processes), processes)
+	}
+}
+
+func TestProcFSScan(t *testing.T) {
+	p := fakeProc(t)
+
+	processes, err := p.scan()
+	if err != nil {
+		t.Fatalf("Failed to scan processes: %v", err)
+	}
+	if len(processes) != 2 {
+		t.Fatalf("Expected 2 processes, got %d: %+v", len(processes), processes)
+	}
+	for _, info := range processes {
+		if info.PID == 1234 && (info.Name != "Game (x64) v2" || info.PPID != 1000) {
+			t.Fatalf("Unexpected scan result: %+v", info)
+		}
+	}
+}
+
+func TestProcFSResolve(t *testing.T) {
+	p := fakeProc(t)
+
+	if exe, err := p.resolveExe(1234); err != nil || exe != "/opt/game/game.bin" {
+		t.Fatalf("Expected the exe link, got %q, %v", exe, err)
+	}
+	if _, err := p.resolveExe(1000); err == nil {
+		t.Fatal("Expected an error for a process without exe link")
+	}
+	if name, err := p.resolveStat(1000); err != nil || name != "launcher" {
+		t.Fatalf("Expected the name from stat, got %q, %v", name, err)
+	}
+	if _, err := p.resolveStat(4321); !errors.Is(err, ErrProcessNotFound) {
+		t.Fatalf("Expected ErrProcessNotFound, got %v", err)
+	}
+}
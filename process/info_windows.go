@@ -0,0 +1,214 @@
+package process
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// list takes a toolhelp snapshot of all processes and enriches every entry with the information that can be queried
+// with limited access rights.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/tlhelp32/nf-tlhelp32-createtoolhelp32snapshot
+func list() ([]Info, error) {
+	entries, err := snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	processes := make([]Info, 0, len(entries))
+	for _, entry := range entries {
+		processes = append(processes, infoFromEntry(entry))
+	}
+	return processes, nil
+}
+
+func get(pid uint32) (Info, error) {
+	entries, err := snapshot()
+	if err != nil {
+		return Info{}, err
+	}
+
+	for _, entry := range entries {
+		if entry.ProcessID == pid {
+			return infoFromEntry(entry), nil
+		}
+	}
+	return Info{}, &Error{Op: "get", PID: pid, Err: ErrProcessNotFound}
+}
+
+// snapshot returns the raw toolhelp entries of all running processes.
+func snapshot() ([]windows.ProcessEntry32, error) {
+	handle, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create process snapshot: %v", err)
+	}
+	defer windows.CloseHandle(handle)
+
+	var entries []windows.ProcessEntry32
+	entry := windows.ProcessEntry32{Size: uint32(unsafe.Sizeof(windows.ProcessEntry32{}))}
+	for err = windows.Process32First(handle, &entry); err == nil; err = windows.Process32Next(handle, &entry) {
+		entries = append(entries, entry)
+	}
+	if err != windows.ERROR_NO_MORE_FILES {
+		return nil, fmt.Errorf("failed to walk process snapshot: %v", err)
+	}
+	return entries, nil
+}
+
+// infoFromEntry converts a toolhelp entry to Info. Everything beyond the snapshot data is best effort, since
+// protected and elevated processes can't be opened by a regular user.
+func infoFromEntry(entry windows.ProcessEntry32) Info {
+	info := Info{
+		PID:  entry.ProcessID,
+		PPID: entry.ParentProcessID,
+		Name: windows.UTF16ToString(entry.ExeFile[:]),
+	}
+
+	var sessionID uint32
+	if err := windows.ProcessIdToSessionId(info.PID, &sessionID); err == nil {
+		info.SessionID = sessionID
+	}
+
+	hProcess, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, info.PID)
+	if err != nil {
+		return info
+	}
+	defer windows.CloseHandle(hProcess)
+
+	if exe, err := queryFullProcessImageName(hProcess); err == nil {
+		info.Exe = exe
+		info.Name = filepath.Base(exe)
+	}
+
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(hProcess, &creation, &exit, &kernel, &user); err == nil {
+		info.StartTime = time.Unix(0, creation.Nanoseconds())
+	}
+
+	if cmdLine, err := queryCommandLine(hProcess); err == nil {
+		info.CmdLine = cmdLine
+	}
+
+	if user, err := queryUser(hProcess); err == nil {
+		info.User = user
+	}
+
+	return info
+}
+
+// queryFullProcessImageName returns the full path of the executable of the given process.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winbase/nf-winbase-queryfullprocessimagenamew
+func queryFullProcessImageName(hProcess windows.Handle) (string, error) {
+	buf := make([]uint16, windows.MAX_LONG_PATH)
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(hProcess, 0, &buf[0], &size); err != nil {
+		return "", err
+	}
+	return windows.UTF16ToString(buf[:size]), nil
+}
+
+// queryCommandLine returns the command line of the given process. This requires Windows 8.1 or newer.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winternl/nf-winternl-ntqueryinformationprocess
+func queryCommandLine(hProcess windows.Handle) (string, error) {
+	var size uint32
+	// the first call is expected to fail and only reports the required buffer size
+	_ = windows.NtQueryInformationProcess(hProcess, windows.ProcessCommandLineInformation, nil, 0, &size)
+	if size == 0 {
+		return "", fmt.Errorf("failed to query command line size")
+	}
+
+	buf := make([]byte, size)
+	if err := windows.NtQueryInformationProcess(hProcess, windows.ProcessCommandLineInformation, unsafe.Pointer(&buf[0]), size, &size); err != nil {
+		return "", err
+	}
+	return (*windows.NTUnicodeString)(unsafe.Pointer(&buf[0])).String(), nil
+}
+
+// queryUser returns the account owning the given process in the DOMAIN\User format.
+func queryUser(hProcess windows.Handle) (string, error) {
+	var token windows.Token
+	if err := windows.OpenProcessToken(hProcess, windows.TOKEN_QUERY, &token); err != nil {
+		return "", err
+	}
+	defer token.Close()
+
+	tokenUser, err := token.GetTokenUser()
+	if err != nil {
+		return "", err
+	}
+
+	account, domain, _, err := tokenUser.User.Sid.LookupAccount("")
+	if err != nil {
+		return tokenUser.User.Sid.String(), nil
+	}
+	return domain + `\` + account, nil
+}
+
+// entryKey identifies a toolhelp entry across scans without opening the process.
+type entryKey struct {
+	pid  uint32
+	ppid uint32
+	name string
+}
+
+var (
+	// startTimes caches the start time of every process seen by the last scan, so only new processes are opened.
+	startTimes   = make(map[entryKey]time.Time)
+	startTimesMu sync.Mutex
+)
+
+// scan only returns the data contained in the toolhelp snapshot and the start time, which tells processes apart if a
+// PID is reused. The start time is only queried for entries that were not part of the previous scan, every other
+// entry reuses the cached value.
+func scan() ([]Info, error) {
+	entries, err := snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	startTimesMu.Lock()
+	defer startTimesMu.Unlock()
+
+	seen := make(map[entryKey]time.Time, len(entries))
+	processes := make([]Info, 0, len(entries))
+	for _, entry := range entries {
+		key := entryKey{pid: entry.ProcessID, ppid: entry.ParentProcessID, name: windows.UTF16ToString(entry.ExeFile[:])}
+		start, ok := startTimes[key]
+		if !ok {
+			start = startTime(entry.ProcessID)
+		}
+		seen[key] = start
+		processes = append(processes, Info{
+			PID:       key.pid,
+			PPID:      key.ppid,
+			Name:      key.name,
+			StartTime: start,
+		})
+	}
+	startTimes = seen
+	return processes, nil
+}
+
+// startTime returns when the process was created, or the zero time if it can't be opened.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/processthreadsapi/nf-processthreadsapi-getprocesstimes
+func startTime(pid uint32) time.Time {
+	hProcess, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return time.Time{}
+	}
+	defer windows.CloseHandle(hProcess)
+
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(hProcess, &creation, &exit, &kernel, &user); err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, creation.Nanoseconds())
+}
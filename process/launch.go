@@ -0,0 +1,26 @@
+package process
+
+import (
+	"log"
+	"os/exec"
+	"path/filepath"
+)
+
+// Launch starts the executable at the given path in its own directory, games often expect their data next to the
+// executable. The process is not waited for.
+//
+// Returns an error if the process could not be started.
+func Launch(path string) error {
+	cmd := exec.Command(path)
+	if filepath.IsAbs(path) {
+		cmd.Dir = filepath.Dir(path)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	log.Printf("Launched %s with PID %d\n", path, cmd.Process.Pid)
+
+	// release the resources of the process once it exits
+	go cmd.Wait()
+	return nil
+}
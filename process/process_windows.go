@@ -3,22 +3,15 @@ package process
 import (
 	"fmt"
 	"path/filepath"
-	"syscall"
 	"unsafe"
 
-	"github.com/StackExchange/wmi"
 	"golang.org/x/sys/windows"
 )
 
 var (
-	user32   = windows.NewLazySystemDLL("user32.dll")
-	kernel32 = syscall.NewLazyDLL("kernel32.dll")
-	psapi    = syscall.NewLazyDLL("psapi.dll")
+	user32 = windows.NewLazySystemDLL("user32.dll")
 
 	procGetWindowThreadProcessId = user32.NewProc("GetWindowThreadProcessId")
-	procOpenProcess              = kernel32.NewProc("OpenProcess")
-	procGetModuleFileNameEx      = psapi.NewProc("GetModuleFileNameExW")
-	procCloseHandle              = kernel32.NewProc("CloseHandle")
 )
 
 const (
@@ -35,19 +28,17 @@ type Win32_Process struct {
 // GetProcessIDByExecutable tries to find the PID (Process ID) of a process with
 // the given executable.
 //
-// Returns either the PID as a uint32 or an error if no PID could be found.
+// Returns either the PID as a uint32 or an error wrapping ErrProcessNotFound if no process is running the executable.
 func GetProcessIDByExecutable(executable string) (uint32, error) {
-	var processes []Win32_Process
-	query := fmt.Sprintf("SELECT ProcessID, Name FROM Win32_Process WHERE Name='%s'", executable)
-	err := wmi.Query(query, &processes)
+	pids, err := FindByName(executable)
 	if err != nil {
-		return 0, fmt.Errorf("error querying WMI: %v", err)
+		return 0, err
 	}
 
-	if len(processes) > 0 {
-		return processes[0].ProcessID, nil
+	if len(pids) > 0 {
+		return pids[0], nil
 	}
-	return 0, nil
+	return 0, &Error{Op: "find", Name: executable, Err: ErrProcessNotFound}
 }
 
 // GetProcessIDFromWindow tries to find the PID (Process ID) of a process with
@@ -58,7 +49,7 @@ func GetProcessIDFromWindow(hWnd uintptr) (uint32, error) {
 	var pid uint32
 	_, _, err := procGetWindowThreadProcessId.Call(hWnd, uintptr(unsafe.Pointer(&pid)))
 	if pid == 0 {
-		return 0, fmt.Errorf("failed to get PID: %v", err)
+		return 0, fmt.Errorf("failed to get PID of window %#x: %v", hWnd, err)
 	}
 	return pid, nil
 }
@@ -66,26 +57,17 @@ func GetProcessIDFromWindow(hWnd uintptr) (uint32, error) {
 // GetFullExecutableFromPID tries to get the full path of the executable of a process
 // with the given PID (Process ID). E.g. C:/Applications/Program.exe
 //
-// Returns either the Executable as a string or an error if the executable could not be found.
+// The least privileged strategy that works is used, see ResolveExecutable. Some strategies only see the name of the
+// executable, which is returned instead of the path then.
+//
+// Returns either the Executable as a string or an *Error if the executable could not be found. The error wraps
+// ErrProcessNotFound if the process doesn't exist and ErrAccessDenied or ErrTargetElevated if it can't be opened.
 func GetFullExecutableFromPID(pid uint32) (string, error) {
-	hProcess, _, _ := procOpenProcess.Call(PROCESS_QUERY_INFORMATION|PROCESS_VM_READ, 0, uintptr(pid))
-	if hProcess == 0 {
-		return "", fmt.Errorf("failed to open process: %v", syscall.GetLastError())
-	}
-	defer func() {
-		ret, _, err := procCloseHandle.Call(hProcess)
-		if ret == 0 {
-			fmt.Printf("Failed to close handle: %v\n", err)
-		}
-	}()
-
-	exePath := make([]uint16, syscall.MAX_PATH)
-	_, _, err := procGetModuleFileNameEx.Call(hProcess, 0, uintptr(unsafe.Pointer(&exePath[0])), syscall.MAX_PATH)
-	if err != syscall.Errno(0) {
-		return "", fmt.Errorf("failed to get module file name: %v", err)
+	resolution, err := ResolveExecutable(pid)
+	if err != nil {
+		return "", err
 	}
-
-	return syscall.UTF16ToString(exePath), nil
+	return resolution.Path, nil
 }
 
 // GetExecutableFromPID functions like GetFullExectuableFromPID but returns only the executable's name.
@@ -0,0 +1,121 @@
+package process
+
+import (
+	"errors"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// resolveTTL is how long a resolved executable is cached. It is short enough to make a reused PID unlikely but spares
+// the system calls for the bursts of lookups caused by a single window event.
+const resolveTTL = 2 * time.Second
+
+// Strategy is a named way of resolving the executable of a process, e.g. a system call that requires certain access
+// rights.
+type Strategy struct {
+	Name string
+	// Resolve returns the full path of the executable or, if the strategy can't see it, only its name.
+	Resolve func(pid uint32) (string, error)
+}
+
+// Resolution is the executable of a process and the name of the strategy that resolved it.
+type Resolution struct {
+	Path     string
+	Strategy string
+}
+
+// Name returns the name of the executable, e.g. Program.exe.
+func (r Resolution) Name() string {
+	return filepath.Base(r.Path)
+}
+
+// ResolverChain resolves the executable of a process by trying its strategies in order until one succeeds. Successful
+// resolutions are cached for TTL.
+type ResolverChain struct {
+	Strategies []Strategy
+	TTL        time.Duration
+
+	mu    sync.Mutex
+	cache map[uint32]cachedResolution
+	now   func() time.Time
+}
+
+type cachedResolution struct {
+	Resolution
+	expires time.Time
+}
+
+// NewResolverChain returns a ResolverChain trying the strategies in the given order.
+func NewResolverChain(ttl time.Duration, strategies ...Strategy) *ResolverChain {
+	return &ResolverChain{
+		Strategies: strategies,
+		TTL:        ttl,
+		cache:      make(map[uint32]cachedResolution),
+		now:        time.Now,
+	}
+}
+
+// Resolve returns the executable of the process with the given PID.
+//
+// Returns either the Resolution or an error if all strategies failed. The error wraps ErrProcessNotFound if any
+// strategy found the process doesn't exist, otherwise it is the error of the first strategy, which is the most
+// specific one, e.g. ErrTargetElevated.
+func (c *ResolverChain) Resolve(pid uint32) (Resolution, error) {
+	now := c.now()
+
+	c.mu.Lock()
+	cached, ok := c.cache[pid]
+	c.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.Resolution, nil
+	}
+
+	var first error
+	for i, strategy := range c.Strategies {
+		path, err := strategy.Resolve(pid)
+		if errors.Is(err, ErrProcessNotFound) {
+			return Resolution{}, err
+		}
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+
+		resolution := Resolution{Path: path, Strategy: strategy.Name}
+		if i > 0 {
+			log.Printf("Resolved executable of process %d with %s: %s\n", pid, strategy.Name, path)
+		}
+		c.mu.Lock()
+		c.prune(now)
+		c.cache[pid] = cachedResolution{Resolution: resolution, expires: now.Add(c.TTL)}
+		c.mu.Unlock()
+		return resolution, nil
+	}
+
+	if first == nil {
+		first = &Error{Op: "resolve executable of", PID: pid, Err: errors.New("no strategy available")}
+	}
+	return Resolution{}, first
+}
+
+// prune removes expired resolutions, so the cache doesn't grow with every process ever seen.
+func (c *ResolverChain) prune(now time.Time) {
+	for pid, cached := range c.cache {
+		if !now.Before(cached.expires) {
+			delete(c.cache, pid)
+		}
+	}
+}
+
+// resolver is the chain used by ResolveExecutable with the strategies available on this platform.
+var resolver = NewResolverChain(resolveTTL, strategies()...)
+
+// ResolveExecutable returns the executable of the process with the given PID using the least privileged strategy
+// that works, see ResolverChain.
+func ResolveExecutable(pid uint32) (Resolution, error) {
+	return resolver.Resolve(pid)
+}
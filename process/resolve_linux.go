@@ -0,0 +1,41 @@
+package process
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// strategies returns the strategies of the resolver. The exe link of processes of other users can't be read, their
+// stat file always can, but only contains the (truncated) name of the executable.
+func strategies() []Strategy {
+	return []Strategy{
+		{Name: "exe link", Resolve: proc.resolveExe},
+		{Name: "stat", Resolve: proc.resolveStat},
+	}
+}
+
+func (p procFS) resolveExe(pid uint32) (string, error) {
+	exe, err := os.Readlink(filepath.Join(p.root, strconv.FormatUint(uint64(pid), 10), "exe"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", &Error{Op: "resolve executable of", PID: pid, Err: ErrProcessNotFound}
+	}
+	if err != nil {
+		return "", AccessError("resolve executable of", pid, err)
+	}
+	return strings.TrimSuffix(exe, " (deleted)"), nil
+}
+
+func (p procFS) resolveStat(pid uint32) (string, error) {
+	st, err := p.readStat(filepath.Join(p.root, strconv.FormatUint(uint64(pid), 10)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", &Error{Op: "resolve executable of", PID: pid, Err: ErrProcessNotFound}
+	}
+	if err != nil {
+		return "", err
+	}
+	return st.comm, nil
+}
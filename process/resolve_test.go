@@ -0,0 +1,102 @@
+package process
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeStrategy is a Strategy returning fixed results and counting its calls.
+type fakeStrategy struct {
+	paths map[uint32]string
+	err   error
+	calls int
+}
+
+func (f *fakeStrategy) strategy(name string) Strategy {
+	return Strategy{Name: name, Resolve: func(pid uint32) (string, error) {
+		f.calls++
+		if path, ok := f.paths[pid]; ok {
+			return path, nil
+		}
+		return "", f.err
+	}}
+}
+
+// testChain returns a chain of the strategies with a clock controlled by the test.
+func testChain(strategies ...Strategy) (*ResolverChain, *time.Time) {
+	chain := NewResolverChain(time.Second, strategies...)
+	now := time.Unix(1700000000, 0)
+	chain.now = func() time.Time { return now }
+	return chain, &now
+}
+
+func TestResolverChainFallback(t *testing.T) {
+	limited := &fakeStrategy{paths: map[uint32]string{1: `C:\Games\Game.exe`}, err: &Error{Op: "open", PID: 2, Err: ErrTargetElevated}}
+	toolhelp := &fakeStrategy{paths: map[uint32]string{1: "Game.exe", 2: "Protected.exe"}, err: errors.New("not in snapshot")}
+	chain, _ := testChain(limited.strategy("limited query"), toolhelp.strategy("toolhelp"))
+
+	resolution, err := chain.Resolve(1)
+	if err != nil || resolution != (Resolution{Path: `C:\Games\Game.exe`, Strategy: "limited query"}) {
+		t.Fatalf("Expected the first strategy to resolve the path, got %+v, %v", resolution, err)
+	}
+	if toolhelp.calls != 0 {
+		t.Fatal("Expected the fallback not to be used")
+	}
+
+	resolution, err = chain.Resolve(2)
+	if err != nil || resolution.Strategy != "toolhelp" || resolution.Name() != "Protected.exe" {
+		t.Fatalf("Expected the fallback to resolve the name, got %+v, %v", resolution, err)
+	}
+
+	// the error of the first strategy is the most specific one
+	if _, err := chain.Resolve(3); !errors.Is(err, ErrTargetElevated) {
+		t.Fatalf("Expected the error of the first strategy, got %v", err)
+	}
+}
+
+func TestResolverChainNotFound(t *testing.T) {
+	limited := &fakeStrategy{err: &Error{Op: "open", PID: 1, Err: ErrAccessDenied}}
+	toolhelp := &fakeStrategy{err: &Error{Op: "find", PID: 1, Err: ErrProcessNotFound}}
+	wmi := &fakeStrategy{paths: map[uint32]string{1: "Game.exe"}}
+	chain, _ := testChain(limited.strategy("limited query"), toolhelp.strategy("toolhelp"), wmi.strategy("wmi"))
+
+	if _, err := chain.Resolve(1); !errors.Is(err, ErrProcessNotFound) {
+		t.Fatalf("Expected ErrProcessNotFound, got %v", err)
+	}
+	if wmi.calls != 0 {
+		t.Fatal("Expected no strategy to be tried once the process is known to be gone")
+	}
+
+	if _, err := NewResolverChain(time.Second).Resolve(1); err == nil {
+		t.Fatal("Expected an error for a chain without strategies")
+	}
+}
+
+func TestResolverChainCache(t *testing.T) {
+	limited := &fakeStrategy{paths: map[uint32]string{1: "Game.exe"}, err: &Error{Op: "open", PID: 2, Err: ErrAccessDenied}}
+	chain, now := testChain(limited.strategy("limited query"))
+
+	for range 3 {
+		if _, err := chain.Resolve(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if limited.calls != 1 {
+		t.Fatalf("Expected a single lookup within the TTL, got %d", limited.calls)
+	}
+
+	*now = now.Add(time.Second)
+	if _, err := chain.Resolve(1); err != nil || limited.calls != 2 {
+		t.Fatalf("Expected an expired resolution to be looked up again, got %d calls, %v", limited.calls, err)
+	}
+
+	// failures are not cached
+	if _, err := chain.Resolve(2); err == nil {
+		t.Fatal("Expected an error for an unknown process")
+	}
+	limited.paths[2] = "Other.exe"
+	if resolution, err := chain.Resolve(2); err != nil || resolution.Path != "Other.exe" {
+		t.Fatalf("Expected the process to be resolved, got %+v, %v", resolution, err)
+	}
+}
@@ -0,0 +1,80 @@
+package process
+
+import (
+	"fmt"
+
+	"github.com/StackExchange/wmi"
+	"golang.org/x/sys/windows"
+)
+
+// strategies returns the strategies of the resolver from the least to the most expensive. None of them reads the
+// memory of the process, which fails for protected processes and is flagged by some anti-cheat systems.
+func strategies() []Strategy {
+	return []Strategy{
+		{Name: "limited query", Resolve: resolveLimitedQuery},
+		{Name: "toolhelp", Resolve: resolveToolhelp},
+		{Name: "wmi", Resolve: resolveWMI},
+	}
+}
+
+// resolveLimitedQuery queries the full image name with PROCESS_QUERY_LIMITED_INFORMATION, which is granted for most
+// processes including elevated ones.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/procthread/process-security-and-access-rights
+func resolveLimitedQuery(pid uint32) (string, error) {
+	hProcess, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err == windows.ERROR_INVALID_PARAMETER {
+		// OpenProcess fails with an invalid parameter for PIDs that are not in use
+		return "", &Error{Op: "open", PID: pid, Err: ErrProcessNotFound}
+	}
+	if err != nil {
+		return "", AccessError("open", pid, err)
+	}
+	defer windows.CloseHandle(hProcess)
+
+	exePath, err := queryFullProcessImageName(hProcess)
+	if err != nil {
+		return "", AccessError("query executable of", pid, err)
+	}
+	return exePath, nil
+}
+
+// resolveToolhelp looks the process up in a toolhelp snapshot, which doesn't open the process at all but only
+// contains the name of the executable.
+func resolveToolhelp(pid uint32) (string, error) {
+	entries, err := snapshot()
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		if entry.ProcessID == pid {
+			return windows.UTF16ToString(entry.ExeFile[:]), nil
+		}
+	}
+	return "", &Error{Op: "find", PID: pid, Err: ErrProcessNotFound}
+}
+
+// win32ProcessPath is the part of the Win32_Process WMI class used by resolveWMI. The path is empty for processes
+// the WMI service can't open.
+type win32ProcessPath struct {
+	Name           string
+	ExecutablePath *string
+}
+
+// resolveWMI queries the WMI service, which is slow but runs with more privileges than FocusFrame.
+func resolveWMI(pid uint32) (string, error) {
+	var processes []win32ProcessPath
+	query := fmt.Sprintf("SELECT Name, ExecutablePath FROM Win32_Process WHERE ProcessId = %d", pid)
+	if err := wmi.Query(query, &processes); err != nil {
+		return "", &Error{Op: "query wmi for", PID: pid, Err: err}
+	}
+	if len(processes) == 0 {
+		return "", &Error{Op: "query wmi for", PID: pid, Err: ErrProcessNotFound}
+	}
+
+	if path := processes[0].ExecutablePath; path != nil && *path != "" {
+		return *path, nil
+	}
+	return processes[0].Name, nil
+}
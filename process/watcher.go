@@ -0,0 +1,227 @@
+package process
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// EventType describes what happened to a process.
+type EventType int
+
+const (
+	Started EventType = iota
+	Exited
+)
+
+func (t EventType) String() string {
+	switch t {
+	case Started:
+		return "started"
+	case Exited:
+		return "exited"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is emitted by a Watcher when a process starts or exits.
+type Event struct {
+	Type EventType
+	Info Info
+}
+
+// Watcher emits an Event for every process that starts or exits while it is being watched.
+//
+// The returned channel is closed once the context is cancelled.
+type Watcher interface {
+	Watch(ctx context.Context) <-chan Event
+}
+
+// SnapshotWatcher detects process starts and exits by diffing cheap snapshots of the process table. Processes that
+// are already running when Watch is called don't produce a Started event.
+type SnapshotWatcher struct {
+	Interval time.Duration
+
+	// scan returns a lightweight view of all running processes, get resolves the full Info of a started process.
+	scan func() ([]Info, error)
+	get  func(pid uint32) (Info, error)
+}
+
+// processKey identifies a process across snapshots. The start time and name guard against PID reuse.
+type processKey struct {
+	pid   uint32
+	start time.Time
+	name  string
+}
+
+// DefaultInterval is how often a SnapshotWatcher takes a snapshot of the process table unless told otherwise.
+const DefaultInterval = 2 * time.Second
+
+// NewWatcher returns a SnapshotWatcher that takes a snapshot of the process table every interval, or every
+// DefaultInterval if the interval is not positive.
+func NewWatcher(interval time.Duration) *SnapshotWatcher {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &SnapshotWatcher{
+		Interval: interval,
+		scan:     scan,
+		get:      Get,
+	}
+}
+
+// Watch starts watching the process table until the context is cancelled.
+func (w *SnapshotWatcher) Watch(ctx context.Context) <-chan Event {
+	events := make(chan Event)
+
+	go func() {
+		defer close(events)
+
+		known, err := w.snapshot()
+		if err != nil {
+			log.Println("Error taking process snapshot:", err)
+			known = make(map[processKey]Info)
+		}
+
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current, err := w.snapshot()
+			if err != nil {
+				log.Println("Error taking process snapshot:", err)
+				continue
+			}
+
+			for key, info := range known {
+				if _, ok := current[key]; ok {
+					continue
+				}
+				if !send(ctx, events, Event{Type: Exited, Info: info}) {
+					return
+				}
+			}
+
+			for key, info := range current {
+				if prev, ok := known[key]; ok {
+					// keep the enriched info of processes we already know
+					current[key] = prev
+					continue
+				}
+				if full, err := w.get(info.PID); err == nil {
+					info = full
+					current[key] = full
+				}
+				if !send(ctx, events, Event{Type: Started, Info: info}) {
+					return
+				}
+			}
+
+			known = current
+		}
+	}()
+
+	return events
+}
+
+// snapshot scans the process table and indexes the result by processKey.
+func (w *SnapshotWatcher) snapshot() (map[processKey]Info, error) {
+	processes, err := w.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make(map[processKey]Info, len(processes))
+	for _, info := range processes {
+		snapshot[processKey{pid: info.PID, start: info.StartTime, name: info.Name}] = info
+	}
+	return snapshot, nil
+}
+
+// send delivers the event unless the context is cancelled first.
+func send(ctx context.Context, events chan<- Event, event Event) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// FakeWatcher is a Watcher for tests that only emits the events passed to Start and Exit.
+type FakeWatcher struct {
+	mu          sync.Mutex
+	subscribers []fakeSubscriber
+}
+
+type fakeSubscriber struct {
+	events chan Event
+	done   <-chan struct{}
+}
+
+// Watch subscribes to the events of the FakeWatcher until the context is cancelled.
+func (f *FakeWatcher) Watch(ctx context.Context) <-chan Event {
+	in := make(chan Event, 16)
+	out := make(chan Event)
+
+	f.mu.Lock()
+	f.subscribers = append(f.subscribers, fakeSubscriber{events: in, done: ctx.Done()})
+	f.mu.Unlock()
+
+	go func() {
+		defer close(out)
+		defer f.unsubscribe(in)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-in:
+				if !send(ctx, out, event) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Start emits a Started event for the given process.
+func (f *FakeWatcher) Start(info Info) {
+	f.emit(Event{Type: Started, Info: info})
+}
+
+// Exit emits an Exited event for the given process.
+func (f *FakeWatcher) Exit(info Info) {
+	f.emit(Event{Type: Exited, Info: info})
+}
+
+func (f *FakeWatcher) emit(event Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, subscriber := range f.subscribers {
+		select {
+		case subscriber.events <- event:
+		case <-subscriber.done:
+		}
+	}
+}
+
+func (f *FakeWatcher) unsubscribe(in chan Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, subscriber := range f.subscribers {
+		if subscriber.events == in {
+			f.subscribers = append(f.subscribers[:i], f.subscribers[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTable is a process table whose content can be changed while a SnapshotWatcher scans it.
+type fakeTable struct {
+	mu        sync.Mutex
+	processes []Info
+}
+
+func (f *fakeTable) set(processes ...Info) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.processes = processes
+}
+
+func (f *fakeTable) scan() ([]Info, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Info(nil), f.processes...), nil
+}
+
+func (f *fakeTable) get(pid uint32) (Info, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, info := range f.processes {
+		if info.PID == pid {
+			info.CmdLine = "full " + info.Name
+			return info, nil
+		}
+	}
+	return Info{}, fmt.Errorf("process %d not found", pid)
+}
+
+func receive(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for event")
+		return Event{}
+	}
+}
+
+func TestSnapshotWatcher(t *testing.T) {
+	table := &fakeTable{}
+	table.set(Info{PID: 1, Name: "init"})
+
+	watcher := &SnapshotWatcher{Interval: 5 * time.Millisecond, scan: table.scan, get: table.get}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := watcher.Watch(ctx)
+
+	// give the watcher time to take its baseline snapshot, pre-existing processes must not be reported
+	time.Sleep(20 * time.Millisecond)
+	table.set(Info{PID: 1, Name: "init"}, Info{PID: 42, Name: "game.exe"})
+
+	event := receive(t, events)
+	if event.Type != Started || event.Info.PID != 42 || event.Info.CmdLine != "full game.exe" {
+		t.Fatalf("Expected enriched started event for PID 42, got %s %+v", event.Type, event.Info)
+	}
+
+	table.set(Info{PID: 1, Name: "init"})
+
+	event = receive(t, events)
+	if event.Type != Exited || event.Info.PID != 42 || event.Info.CmdLine != "full game.exe" {
+		t.Fatalf("Expected exited event for PID 42, got %s %+v", event.Type, event.Info)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestSnapshotWatcherPIDReuse(t *testing.T) {
+	table := &fakeTable{}
+	table.set(Info{PID: 42, Name: "launcher.exe"})
+
+	watcher := &SnapshotWatcher{Interval: 5 * time.Millisecond, scan: table.scan, get: table.get}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := watcher.Watch(ctx)
+
+	time.Sleep(20 * time.Millisecond)
+	table.set(Info{PID: 42, Name: "game.exe"})
+
+	seen := map[EventType]string{}
+	for i := 0; i < 2; i++ {
+		event := receive(t, events)
+		seen[event.Type] = event.Info.Name
+	}
+	if seen[Exited] != "launcher.exe" || seen[Started] != "game.exe" {
+		t.Fatalf("Expected launcher to exit and game to start, got %v", seen)
+	}
+}
+
+func TestFakeWatcher(t *testing.T) {
+	fake := &FakeWatcher{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := fake.Watch(ctx)
+
+	fake.Start(Info{PID: 7, Name: "game.exe"})
+	fake.Exit(Info{PID: 7, Name: "game.exe"})
+
+	if event := receive(t, events); event.Type != Started || event.Info.PID != 7 {
+		t.Fatalf("Unexpected event %s %+v", event.Type, event.Info)
+	}
+	if event := receive(t, events); event.Type != Exited || event.Info.PID != 7 {
+		t.Fatalf("Unexpected event %s %+v", event.Type, event.Info)
+	}
+
+	cancel()
+	for range events {
+	}
+
+	// emitting after all subscribers are gone must not block
+	fake.Start(Info{PID: 8})
+}
+
+func TestNewWatcherDefaultInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if w := NewWatcher(interval); w.Interval != DefaultInterval {
+			t.Errorf("Expected NewWatcher(%v) to watch every %v, got %v", interval, DefaultInterval, w.Interval)
+		}
+	}
+}
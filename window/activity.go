@@ -0,0 +1,52 @@
+package window
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+)
+
+// activityLimit is the number of activities kept in the history, older ones are dropped.
+const activityLimit = 100
+
+// Activity is something FocusFrame did on its own that the user might wonder about, e.g. keeping a window from taking
+// focus.
+type Activity struct {
+	Time       time.Time
+	Executable string // App the activity concerns
+	Message    string
+}
+
+// OnActivity is called with every activity added to the history. It must not block.
+var OnActivity func(Activity)
+
+var activities = struct {
+	sync.Mutex
+	list []Activity
+}{}
+
+// Activities returns the history of activities, the latest one last.
+func Activities() []Activity {
+	activities.Lock()
+	defer activities.Unlock()
+	return slices.Clone(activities.list)
+}
+
+// recordActivity adds an activity concerning the executable to the history and logs it.
+func recordActivity(executable string, format string, args ...any) {
+	a := Activity{Time: time.Now(), Executable: executable, Message: fmt.Sprintf(format, args...)}
+	log.Println(a.Message)
+
+	activities.Lock()
+	activities.list = append(activities.list, a)
+	if len(activities.list) > activityLimit {
+		activities.list = slices.Delete(activities.list, 0, len(activities.list)-activityLimit)
+	}
+	activities.Unlock()
+
+	if OnActivity != nil {
+		OnActivity(a)
+	}
+}
@@ -0,0 +1,192 @@
+package window
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/lxn/win"
+	"golang.org/x/sys/windows"
+)
+
+// wmCall wakes the backdrop thread up to run the queued calls.
+const wmCall = win.WM_APP + 1
+
+var (
+	gdi32                 = windows.NewLazySystemDLL("gdi32.dll")
+	procCreateSolidBrush  = gdi32.NewProc("CreateSolidBrush")
+	procFillRect          = user32.NewProc("FillRect")
+	procCreateWindowEx    = user32.NewProc("CreateWindowExW")
+	procPostThreadMessage = user32.NewProc("PostThreadMessageW")
+
+	backdropClass   = windows.StringToUTF16Ptr("FocusFrameBackdrop")
+	backdropWndProc = syscall.NewCallback(backdropProc)
+	backdrops       = &backdropThread{}
+)
+
+// backdropThread is the OS thread owning the backdrop windows. A window belongs to the thread that created it, which
+// has to process its messages or the window stops responding.
+type backdropThread struct {
+	once  sync.Once
+	err   error // why the thread could not be started
+	id    uint32
+	calls chan func()
+	// brushes paint the backdrops, only used on the thread
+	brushes map[win.HWND]uintptr
+}
+
+// start runs the thread unless it is already running.
+//
+// Returns an error if the window class of the backdrops could not be registered.
+func (t *backdropThread) start() error {
+	t.once.Do(func() {
+		t.calls = make(chan func(), 8)
+		t.brushes = make(map[win.HWND]uintptr)
+		ready := make(chan error)
+		go t.run(ready)
+		t.err = <-ready
+	})
+	return t.err
+}
+
+// run processes the messages of the backdrop windows and the queued calls. It never returns, the thread lives as long
+// as FocusFrame does.
+func (t *backdropThread) run(ready chan<- error) {
+	runtime.LockOSThread()
+
+	var msg win.MSG
+	// PostThreadMessage fails until the thread has a message queue, peeking creates it
+	win.PeekMessage(&msg, 0, win.WM_USER, win.WM_USER, win.PM_NOREMOVE)
+	t.id = win.GetCurrentThreadId()
+
+	class := win.WNDCLASSEX{
+		LpfnWndProc:   backdropWndProc,
+		HInstance:     win.GetModuleHandle(nil),
+		HCursor:       win.LoadCursor(0, win.MAKEINTRESOURCE(win.IDC_ARROW)),
+		LpszClassName: backdropClass,
+	}
+	class.CbSize = uint32(unsafe.Sizeof(class))
+	if win.RegisterClassEx(&class) == 0 {
+		ready <- fmt.Errorf("could not register the window class of backdrops")
+		return
+	}
+	ready <- nil
+
+	for win.GetMessage(&msg, 0, 0, 0) > 0 {
+		if msg.HWnd == 0 && msg.Message == wmCall {
+			t.runCalls()
+			continue
+		}
+		win.TranslateMessage(&msg)
+		win.DispatchMessage(&msg)
+	}
+}
+
+// runCalls runs the queued calls on the thread.
+func (t *backdropThread) runCalls() {
+	for {
+		select {
+		case call := <-t.calls:
+			call()
+		default:
+			return
+		}
+	}
+}
+
+// call runs f on the thread and waits for it to return.
+//
+// Returns an error if the thread could not be started or woken up.
+func (t *backdropThread) call(f func()) error {
+	if err := t.start(); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	t.calls <- func() {
+		defer close(done)
+		f()
+	}
+	if ret, _, err := procPostThreadMessage.Call(uintptr(t.id), wmCall, 0, 0); ret == 0 {
+		return fmt.Errorf("could not wake up the backdrop thread: %v", err)
+	}
+	<-done
+	return nil
+}
+
+// backdropProc is the window procedure of the backdrops, which paints them in their color.
+func backdropProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	if msg == win.WM_ERASEBKGND {
+		var rc win.RECT
+		win.GetClientRect(hwnd, &rc)
+		procFillRect.Call(wParam, uintptr(unsafe.Pointer(&rc)), backdrops.brushes[hwnd])
+		return 1
+	}
+	return win.DefWindowProc(hwnd, msg, wParam, lParam)
+}
+
+// ShowBackdrop creates a borderless window of the color which neither takes focus nor shows up in the taskbar.
+//
+// This function uses the CreateWindowExW and SetWindowPos functions from winuser.h.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-createwindowexw
+func (b win32Backend) ShowBackdrop(r Rect, color Color, above Handle) (Handle, error) {
+	var h win.HWND
+	var err error
+	callErr := backdrops.call(func() {
+		ret, _, createErr := procCreateWindowEx.Call(
+			uintptr(WS_EX_NOACTIVATE|WS_EX_TOOLWINDOW),
+			uintptr(unsafe.Pointer(backdropClass)),
+			0,
+			uintptr(WS_POPUP),
+			uintptr(r.Left), uintptr(r.Top), uintptr(r.Width()), uintptr(r.Height()),
+			0, 0, uintptr(win.GetModuleHandle(nil)), 0,
+		)
+		if ret == 0 {
+			err = fmt.Errorf("could not create backdrop: %v", createErr)
+			return
+		}
+		h = win.HWND(ret)
+		brush, _, _ := procCreateSolidBrush.Call(uintptr(color.R) | uintptr(color.G)<<8 | uintptr(color.B)<<16)
+		backdrops.brushes[h] = brush
+
+		if result, _, posErr := procSetWindowPos.Call(
+			uintptr(h), uintptr(above), 0, 0, 0, 0,
+			uintptr(SWP_NOMOVE|SWP_NOSIZE|SWP_NOACTIVATE|win.SWP_SHOWWINDOW),
+		); result == 0 {
+			err = windowError("place backdrop behind", above, posErr)
+			// the caller gets no handle to hide the backdrop with
+			win.DestroyWindow(h)
+			win.DeleteObject(win.HGDIOBJ(brush))
+			delete(backdrops.brushes, h)
+			h = 0
+		}
+	})
+	if callErr != nil {
+		return 0, callErr
+	}
+	return Handle(h), err
+}
+
+// HideBackdrop destroys a backdrop on the thread that created it.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-destroywindow
+func (b win32Backend) HideBackdrop(h Handle) error {
+	var err error
+	callErr := backdrops.call(func() {
+		hwnd := win.HWND(h)
+		brush, ok := backdrops.brushes[hwnd]
+		if !ok {
+			err = fmt.Errorf("%w: no backdrop %#x", ErrWindowNotFound, uintptr(h))
+			return
+		}
+		win.DestroyWindow(hwnd)
+		win.DeleteObject(win.HGDIOBJ(brush))
+		delete(backdrops.brushes, hwnd)
+	})
+	if callErr != nil {
+		return callErr
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package window
+
+import "fmt"
+
+// Backend is the interface FocusFrame uses to inspect and arrange windows of the underlying windowing system.
+//
+// The Windows implementation talks to user32, tests use the in-memory Fake.
+type Backend interface {
+	// Windows returns all visible top-level windows in z-order, the topmost window first.
+	Windows() ([]Info, error)
+	// Info returns the current state of the window with the given handle.
+	Info(h Handle) (Info, error)
+	// Foreground returns the handle of the window that currently has focus or 0 if there is none.
+	Foreground() Handle
+	// Responding reports whether the window processes messages, i.e. its application is not hung.
+	Responding(h Handle) bool
+	// SetRect moves and resizes the window so its Rect (including invisible borders) matches r, without changing the
+	// z-order or activating it.
+	SetRect(h Handle, r Rect) error
+	// SetStyle changes the style of the window, see StyleChange.
+	SetStyle(h Handle, change StyleChange) error
+	// SetShowState minimizes, maximizes or restores the window. Minimizing doesn't activate another window.
+	SetShowState(h Handle, state ShowState) error
+	// SetTopmost places the window above all windows which are not topmost, or back among them, without activating it.
+	SetTopmost(h Handle, topmost bool) error
+	// PlaceBehind moves the window directly behind the window above in the z-order without activating it. It stops
+	// being topmost unless above is topmost.
+	PlaceBehind(h Handle, above Handle) error
+	// Activate brings the window to the foreground and gives it focus.
+	Activate(h Handle) error
+	// ShowBackdrop creates a window of a solid color covering r directly behind the window above, e.g. the monitor
+	// behind a game. The backdrop never takes focus.
+	ShowBackdrop(r Rect, color Color, above Handle) (Handle, error)
+	// HideBackdrop destroys a window created by ShowBackdrop.
+	HideBackdrop(h Handle) error
+	// Monitors returns all monitors of the desktop.
+	Monitors() ([]Monitor, error)
+}
+
+// ShowState is whether a window is minimized, maximized or neither.
+type ShowState int
+
+const (
+	ShowNormal ShowState = iota
+	ShowMinimized
+	ShowMaximized
+	// ShowRestored brings a minimized window back the way it was before, normal or maximized, without activating it.
+	ShowRestored
+)
+
+func (s ShowState) String() string {
+	switch s {
+	case ShowNormal:
+		return "normal"
+	case ShowMinimized:
+		return "minimized"
+	case ShowMaximized:
+		return "maximized"
+	case ShowRestored:
+		return "restored"
+	default:
+		return "unknown"
+	}
+}
+
+// Color is a color of the screen.
+type Color struct {
+	R, G, B uint8
+}
+
+// ParseColor parses a color in the form "#rrggbb".
+//
+// Returns either the color or an error if it is malformed.
+func ParseColor(s string) (Color, error) {
+	var c Color
+	if len(s) != 7 || s[0] != '#' {
+		return c, fmt.Errorf("invalid color %q, expected e.g. #202020", s)
+	}
+	if _, err := fmt.Sscanf(s[1:], "%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return c, fmt.Errorf("invalid color %q, expected e.g. #202020", s)
+	}
+	return c, nil
+}
+
+// backend is the Backend used by all package level functions.
+var backend Backend = newBackend()
+
+// CurrentBackend returns the Backend used by the window package.
+func CurrentBackend() Backend {
+	return backend
+}
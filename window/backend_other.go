@@ -0,0 +1,80 @@
+//go:build !windows
+
+package window
+
+import (
+	"errors"
+
+	"github.com/skryvvara/focusframe/process"
+)
+
+// errUnsupported is returned by all backend calls on platforms without a Backend implementation.
+var errUnsupported = errors.New("window management is not supported on this platform")
+
+type unsupportedBackend struct{}
+
+func newBackend() Backend {
+	return unsupportedBackend{}
+}
+
+func (unsupportedBackend) Windows() ([]Info, error) {
+	return nil, errUnsupported
+}
+
+func (unsupportedBackend) Info(h Handle) (Info, error) {
+	return Info{}, errUnsupported
+}
+
+func (unsupportedBackend) Foreground() Handle {
+	return 0
+}
+
+func (unsupportedBackend) Responding(h Handle) bool {
+	return false
+}
+
+func (unsupportedBackend) SetRect(h Handle, r Rect) error {
+	return errUnsupported
+}
+
+// SetStyle is not supported yet. On Linux the frame would be removed through the _MOTIF_WM_HINTS property, which is
+// deferred until there is an X11 backend to set it.
+func (unsupportedBackend) SetStyle(h Handle, change StyleChange) error {
+	return errUnsupported
+}
+
+func (unsupportedBackend) SetShowState(h Handle, state ShowState) error {
+	return errUnsupported
+}
+
+func (unsupportedBackend) SetTopmost(h Handle, topmost bool) error {
+	return errUnsupported
+}
+
+func (unsupportedBackend) PlaceBehind(h Handle, above Handle) error {
+	return errUnsupported
+}
+
+func (unsupportedBackend) Activate(h Handle) error {
+	return errUnsupported
+}
+
+func (unsupportedBackend) ShowBackdrop(r Rect, color Color, above Handle) (Handle, error) {
+	return 0, errUnsupported
+}
+
+func (unsupportedBackend) HideBackdrop(h Handle) error {
+	return errUnsupported
+}
+
+func (unsupportedBackend) Monitors() ([]Monitor, error) {
+	return nil, errUnsupported
+}
+
+func executableOfPID(pid uint32) (string, error) {
+	resolution, err := process.ResolveExecutable(pid)
+	if err != nil {
+		return "", err
+	}
+	return resolution.Name(), nil
+}
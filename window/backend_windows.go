@@ -0,0 +1,461 @@
+package window
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/lxn/win"
+	"github.com/skryvvara/focusframe/process"
+	"golang.org/x/sys/windows"
+)
+
+const (
+	DWMWA_EXTENDED_FRAME_BOUNDS   = 9
+	DWMWA_CLOAKED                 = 14
+	WM_NULL                       = 0x0000
+	SMTO_ABORTIFHUNG              = 0x0002
+	HWND_TOPMOST                  = ^uintptr(0)     // -1
+	HWND_NOTOPMOST                = ^uintptr(0) - 1 // -2
+	EDD_GET_DEVICE_INTERFACE_NAME = 0x00000001
+	SPI_GETWORKAREA               = 0x0030
+
+	// respondTimeout is how long a window may take to process a message before it is considered not responding.
+	respondTimeout = 100 // ms
+)
+
+var (
+	procIsWindow           = user32.NewProc("IsWindow")
+	procIsHungAppWindow    = user32.NewProc("IsHungAppWindow")
+	procSendMessageTimeout = user32.NewProc("SendMessageTimeoutW")
+
+	// enumWindowsCallback is created once since every syscall.NewCallback allocates a callback slot that is never
+	// released.
+	enumWindowsCallback = syscall.NewCallback(collectWindow)
+	enumLock            sync.Mutex
+	enumHandles         []Handle
+
+	procEnumDisplayMonitors = user32.NewProc("EnumDisplayMonitors")
+	enumMonitorsCallback    = syscall.NewCallback(collectMonitor)
+	enumMonitors            []Monitor
+	procGetMonitorInfo      = user32.NewProc("GetMonitorInfoW")
+	procAttachThreadInput   = user32.NewProc("AttachThreadInput")
+	procEnumDisplayDevices  = user32.NewProc("EnumDisplayDevicesW")
+)
+
+// monitorInfoEx is MONITORINFOEXW, which lxn/win lacks.
+type monitorInfoEx struct {
+	win.MONITORINFO
+	Device [32]uint16
+}
+
+// displayDevice is DISPLAY_DEVICEW, which lxn/win lacks.
+type displayDevice struct {
+	Cb           uint32
+	DeviceName   [32]uint16
+	DeviceString [128]uint16
+	StateFlags   uint32
+	DeviceID     [128]uint16
+	DeviceKey    [128]uint16
+}
+
+// executableOfPID is used by the window index to resolve the executable of a process.
+var executableOfPID = process.GetExecutableFromPID
+
+// win32Backend implements Backend using user32.
+type win32Backend struct{}
+
+func newBackend() Backend {
+	return win32Backend{}
+}
+
+// Windows returns all visible top-level windows.
+//
+// This function uses the EnumWindows function from winuser.h.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-enumwindows
+func (b win32Backend) Windows() ([]Info, error) {
+	enumLock.Lock()
+	enumHandles = nil
+	procEnumWindows.Call(enumWindowsCallback, 0)
+	handles := enumHandles
+	enumHandles = nil
+	enumLock.Unlock()
+
+	infos := make([]Info, 0, len(handles))
+	for _, h := range handles {
+		info, err := b.Info(h)
+		if err != nil {
+			// the window was destroyed while enumerating
+			continue
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// collectWindow is the callback for EnumWindows and collects the handles of all visible windows.
+func collectWindow(hwnd syscall.Handle, lParam uintptr) uintptr {
+	visible, _, _ := procIsWindowVisible.Call(uintptr(hwnd))
+	if visible != 0 {
+		enumHandles = append(enumHandles, Handle(hwnd))
+	}
+	return 1
+}
+
+// Info collects the state of the window with the given handle.
+func (b win32Backend) Info(h Handle) (Info, error) {
+	hwnd := win.HWND(h)
+
+	if exists, _, _ := procIsWindow.Call(uintptr(h)); exists == 0 {
+		return Info{}, fmt.Errorf("%w: %#x", ErrWindowNotFound, uintptr(h))
+	}
+
+	info := Info{
+		Handle:    h,
+		Title:     GetWindowText(uintptr(h)),
+		Class:     getClassName(hwnd),
+		Owner:     Handle(win.GetWindow(hwnd, win.GW_OWNER)),
+		Style:     uint32(win.GetWindowLong(hwnd, win.GWL_STYLE)),
+		ExStyle:   uint32(win.GetWindowLong(hwnd, win.GWL_EXSTYLE)),
+		Minimized: win.IsIconic(hwnd),
+		Maximized: win.IsZoomed(hwnd),
+	}
+
+	if parent := win.GetAncestor(hwnd, win.GA_PARENT); parent != win.GetDesktopWindow() {
+		info.Parent = Handle(parent)
+	}
+
+	win.GetWindowThreadProcessId(hwnd, &info.PID)
+
+	visible, _, _ := procIsWindowVisible.Call(uintptr(h))
+	info.Visible = visible != 0
+	info.Cloaked = isCloaked(hwnd)
+
+	var rect win.RECT
+	if win.GetWindowRect(hwnd, &rect) {
+		info.Rect = fromRECT(rect)
+	}
+	info.NormalRect = info.Rect
+	if info.Minimized || info.Maximized {
+		info.NormalRect = normalRect(hwnd, info.ExStyle, info.Rect)
+	}
+
+	// without the compositor (or for minimized windows) there are no invisible borders
+	info.Frame = info.Rect
+	var frame win.RECT
+	if err := windows.DwmGetWindowAttribute(windows.HWND(hwnd), DWMWA_EXTENDED_FRAME_BOUNDS, unsafe.Pointer(&frame), uint32(unsafe.Sizeof(frame))); err == nil {
+		info.Frame = fromRECT(frame)
+	}
+
+	var client win.RECT
+	if win.GetClientRect(hwnd, &client) {
+		origin := win.POINT{X: client.Left, Y: client.Top}
+		win.ClientToScreen(hwnd, &origin)
+		info.ClientRect = Rect{
+			Left:   int(origin.X),
+			Top:    int(origin.Y),
+			Right:  int(origin.X + client.Right - client.Left),
+			Bottom: int(origin.Y + client.Bottom - client.Top),
+		}
+	}
+
+	info.Monitor = monitorFromWindow(hwnd)
+	info.Fullscreen = info.Style&WS_CAPTION != WS_CAPTION && info.Rect.Contains(info.Monitor.Rect)
+
+	return info, nil
+}
+
+// normalRect returns the rect of a minimized or maximized window when it is restored, or fallback if it is unknown.
+// The rect is in workspace coordinates, which start at the work area of the primary monitor unless the window is a
+// tool window, and is converted to screen coordinates.
+//
+// This function uses the GetWindowPlacement function from winuser.h.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/ns-winuser-windowplacement
+func normalRect(hwnd win.HWND, exStyle uint32, fallback Rect) Rect {
+	placement := win.WINDOWPLACEMENT{Length: uint32(unsafe.Sizeof(win.WINDOWPLACEMENT{}))}
+	if !win.GetWindowPlacement(hwnd, &placement) {
+		return fallback
+	}
+	r := fromRECT(placement.RcNormalPosition)
+	if exStyle&WS_EX_TOOLWINDOW != 0 {
+		return r
+	}
+	var work win.RECT
+	if !win.SystemParametersInfo(SPI_GETWORKAREA, 0, unsafe.Pointer(&work), 0) {
+		return r
+	}
+	dx, dy := int(work.Left), int(work.Top)
+	return Rect{Left: r.Left + dx, Top: r.Top + dy, Right: r.Right + dx, Bottom: r.Bottom + dy}
+}
+
+// SetRect moves and resizes the window.
+//
+// This function uses the SetWindowPos function from winuser.h.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-setwindowpos
+func (b win32Backend) SetRect(h Handle, r Rect) error {
+	result, _, err := procSetWindowPos.Call(
+		uintptr(h),
+		0,
+		uintptr(r.Left),
+		uintptr(r.Top),
+		uintptr(r.Width()),
+		uintptr(r.Height()),
+		uintptr(SWP_NOZORDER|SWP_NOACTIVATE),
+	)
+	if result == 0 {
+		return windowError("move window of", h, err)
+	}
+	return nil
+}
+
+// SetStyle changes the style and extended style of the window. Decorations are the caption and the resizable border.
+//
+// This function uses the SetWindowLongW and SetWindowPos functions from winuser.h.
+//
+// See https://learn.microsoft.com/de-de/windows/win32/api/winuser/nf-winuser-setwindowlongw
+func (b win32Backend) SetStyle(h Handle, change StyleChange) error {
+	hwnd := win.HWND(h)
+	style := uint32(win.GetWindowLong(hwnd, win.GWL_STYLE))
+	exStyle := uint32(win.GetWindowLong(hwnd, win.GWL_EXSTYLE))
+	desiredStyle, desiredExStyle := change.Apply(style, exStyle)
+
+	if style == desiredStyle && exStyle == desiredExStyle {
+		log.Println("Window style already correct, no changes needed.")
+	} else {
+		win.SetWindowLong(hwnd, win.GWL_STYLE, int32(desiredStyle))
+		win.SetWindowLong(hwnd, win.GWL_EXSTYLE, int32(desiredExStyle))
+		log.Println("Window style updated.")
+	}
+
+	if !change.FrameChanged {
+		return nil
+	}
+	result, _, err := procSetWindowPos.Call(
+		uintptr(h), 0, 0, 0, 0, 0,
+		uintptr(SWP_NOMOVE|SWP_NOSIZE|SWP_NOZORDER|SWP_NOACTIVATE|SWP_FRAMECHANGED),
+	)
+	if result == 0 {
+		return windowError("update frame of", h, err)
+	}
+	return nil
+}
+
+// SetShowState minimizes, maximizes or restores the window.
+//
+// This function uses the ShowWindow function from winuser.h.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-showwindow
+func (b win32Backend) SetShowState(h Handle, state ShowState) error {
+	var cmd int32
+	switch state {
+	case ShowNormal:
+		cmd = win.SW_RESTORE
+	case ShowMinimized:
+		cmd = win.SW_SHOWMINNOACTIVE
+	case ShowMaximized:
+		cmd = win.SW_MAXIMIZE
+	case ShowRestored:
+		// like SW_RESTORE a minimized window gets maximized again if it was before, but keeps the focus where it is
+		cmd = win.SW_SHOWNOACTIVATE
+	default:
+		return fmt.Errorf("invalid show state %d", state)
+	}
+	win.ShowWindow(win.HWND(h), cmd)
+	return nil
+}
+
+// SetTopmost places the window above all windows which are not topmost, or back among them.
+//
+// This function uses the SetWindowPos function from winuser.h.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-setwindowpos
+func (b win32Backend) SetTopmost(h Handle, topmost bool) error {
+	insertAfter := HWND_NOTOPMOST
+	if topmost {
+		insertAfter = HWND_TOPMOST
+	}
+	result, _, err := procSetWindowPos.Call(
+		uintptr(h), uintptr(insertAfter), 0, 0, 0, 0,
+		uintptr(SWP_NOMOVE|SWP_NOSIZE|SWP_NOACTIVATE),
+	)
+	if result == 0 {
+		return windowError("change z-order of", h, err)
+	}
+	return nil
+}
+
+// PlaceBehind moves the window directly behind the window above in the z-order.
+//
+// This function uses the SetWindowPos function from winuser.h.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-setwindowpos
+func (b win32Backend) PlaceBehind(h Handle, above Handle) error {
+	result, _, err := procSetWindowPos.Call(
+		uintptr(h), uintptr(above), 0, 0, 0, 0,
+		uintptr(SWP_NOMOVE|SWP_NOSIZE|SWP_NOACTIVATE),
+	)
+	if result == 0 {
+		return windowError("change z-order of", h, err)
+	}
+	return nil
+}
+
+// Activate brings the window to the foreground. Windows only lets the process that received the last input take the
+// foreground, so the thread attaches to the input of the foreground window for the time of the call.
+//
+// This function uses the SetForegroundWindow and AttachThreadInput functions from winuser.h.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-setforegroundwindow
+func (b win32Backend) Activate(h Handle) error {
+	// the input is attached to the OS thread, which must not change in between
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	current := win.GetCurrentThreadId()
+	foreground := win.GetWindowThreadProcessId(win.GetForegroundWindow(), nil)
+	if foreground != 0 && foreground != current {
+		if ret, _, _ := procAttachThreadInput.Call(uintptr(current), uintptr(foreground), 1); ret != 0 {
+			defer procAttachThreadInput.Call(uintptr(current), uintptr(foreground), 0)
+		}
+	}
+	if !win.SetForegroundWindow(win.HWND(h)) {
+		return fmt.Errorf("could not bring window %#x to the foreground", uintptr(h))
+	}
+	return nil
+}
+
+// windowError classifies the error of an operation on the window by its process. Changing windows of an elevated
+// process is denied by user interface privilege isolation unless FocusFrame is elevated too.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/winauto/uiauto-securityoverview
+func windowError(op string, h Handle, err error) error {
+	var pid uint32
+	win.GetWindowThreadProcessId(win.HWND(h), &pid)
+	return process.AccessError(op, pid, err)
+}
+
+// isCloaked reports whether the window is cloaked by the desktop window manager. Cloaked windows are visible
+// according to IsWindowVisible but are not shown, e.g. suspended UWP apps or windows on other virtual desktops.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/dwmapi/ne-dwmapi-dwmwindowattribute
+func isCloaked(hwnd win.HWND) bool {
+	var cloaked uint32
+	err := windows.DwmGetWindowAttribute(windows.HWND(hwnd), DWMWA_CLOAKED, unsafe.Pointer(&cloaked), uint32(unsafe.Sizeof(cloaked)))
+	return err == nil && cloaked != 0
+}
+
+// Foreground returns the handle of the foreground window.
+func (b win32Backend) Foreground() Handle {
+	return Handle(GetForegroundWindow())
+}
+
+// Responding reports whether the window processes messages. Windows already flagged as hung by the system are
+// rejected right away, all others need to process a WM_NULL message within respondTimeout.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-ishungappwindow
+// and https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-sendmessagetimeoutw
+func (b win32Backend) Responding(h Handle) bool {
+	if hung, _, _ := procIsHungAppWindow.Call(uintptr(h)); hung != 0 {
+		return false
+	}
+
+	var result uintptr
+	ret, _, _ := procSendMessageTimeout.Call(
+		uintptr(h),
+		WM_NULL,
+		0,
+		0,
+		SMTO_ABORTIFHUNG,
+		respondTimeout,
+		uintptr(unsafe.Pointer(&result)),
+	)
+	return ret != 0
+}
+
+// getClassName returns the name of the window class of the given window. Class names are limited to 256 characters.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-getclassnamew
+func getClassName(hwnd win.HWND) string {
+	buf := make([]uint16, 257)
+	n, err := win.GetClassName(hwnd, &buf[0], len(buf))
+	if err != nil || n == 0 {
+		return ""
+	}
+	return syscall.UTF16ToString(buf[:n])
+}
+
+// monitorFromWindow returns the monitor the window is on, or the nearest one if the window is off screen.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-monitorfromwindow
+func monitorFromWindow(hwnd win.HWND) Monitor {
+	hMonitor := win.MonitorFromWindow(hwnd, win.MONITOR_DEFAULTTONEAREST)
+
+	mi := win.MONITORINFO{CbSize: uint32(unsafe.Sizeof(win.MONITORINFO{}))}
+	if !win.GetMonitorInfo(hMonitor, &mi) {
+		return Monitor{Handle: uintptr(hMonitor)}
+	}
+
+	return Monitor{
+		Handle:   uintptr(hMonitor),
+		Rect:     fromRECT(mi.RcMonitor),
+		WorkArea: fromRECT(mi.RcWork),
+		Primary:  mi.DwFlags&win.MONITORINFOF_PRIMARY != 0,
+	}
+}
+
+// Monitors returns all monitors of the desktop.
+//
+// This function uses the EnumDisplayMonitors function from winuser.h.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-enumdisplaymonitors
+func (b win32Backend) Monitors() ([]Monitor, error) {
+	enumLock.Lock()
+	defer enumLock.Unlock()
+	enumMonitors = nil
+	if ret, _, err := procEnumDisplayMonitors.Call(0, 0, enumMonitorsCallback, 0); ret == 0 {
+		return nil, err
+	}
+	monitors := enumMonitors
+	enumMonitors = nil
+	return monitors, nil
+}
+
+// collectMonitor is the callback for EnumDisplayMonitors and collects all monitors.
+func collectMonitor(hMonitor win.HMONITOR, hdc win.HDC, rect *win.RECT, lParam uintptr) uintptr {
+	var mi monitorInfoEx
+	mi.CbSize = uint32(unsafe.Sizeof(mi))
+	if ret, _, _ := procGetMonitorInfo.Call(uintptr(hMonitor), uintptr(unsafe.Pointer(&mi))); ret != 0 {
+		enumMonitors = append(enumMonitors, Monitor{
+			Handle:   uintptr(hMonitor),
+			Rect:     fromRECT(mi.RcMonitor),
+			WorkArea: fromRECT(mi.RcWork),
+			Primary:  mi.DwFlags&win.MONITORINFOF_PRIMARY != 0,
+			ID:       displayID(&mi.Device[0]),
+		})
+	}
+	return 1
+}
+
+// displayID returns the ID of the monitor attached to the display adapter with the given device name, or an empty
+// string if it can't be determined.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-enumdisplaydevicesw
+func displayID(device *uint16) string {
+	dd := displayDevice{}
+	dd.Cb = uint32(unsafe.Sizeof(dd))
+	if ret, _, _ := procEnumDisplayDevices.Call(uintptr(unsafe.Pointer(device)), 0, uintptr(unsafe.Pointer(&dd)), EDD_GET_DEVICE_INTERFACE_NAME); ret == 0 {
+		return ""
+	}
+	return monitorID(windows.UTF16ToString(dd.DeviceID[:]))
+}
+
+// fromRECT converts a win32 RECT to a Rect.
+func fromRECT(r win.RECT) Rect {
+	return Rect{Left: int(r.Left), Top: int(r.Top), Right: int(r.Right), Bottom: int(r.Bottom)}
+}
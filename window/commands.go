@@ -0,0 +1,206 @@
+package window
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// errNotManaged is returned by commands for executables that are not managed apps.
+var errNotManaged = errors.New("not a managed app")
+
+// focusedApp returns the focused window and the executable owning it.
+func focusedApp() (Handle, string, error) {
+	h := backend.Foreground()
+	if h == 0 {
+		return 0, "", fmt.Errorf("%w: no window has focus", ErrWindowNotFound)
+	}
+	_, executable, err := ownerOfWindow(h)
+	if err != nil {
+		return 0, "", err
+	}
+	return h, executable, nil
+}
+
+// ToggleForegroundApp takes the currently focused window and either adds its executable to the list of
+// managed applications or removes it from the list if it is already on it.
+//
+// Returns an error if the executable of the focused window could not be found or its settings could not be applied.
+func ToggleForegroundApp() error {
+	_, executable, err := focusedApp()
+	if err != nil {
+		return err
+	}
+
+	if isManaged(executable) {
+		config.RemoveApplication(executable)
+		Unmanage(executable)
+		log.Printf("Stopped managing %s\n", executable)
+		return nil
+	}
+
+	config.AddApplication(executable)
+	log.Printf("Started managing %s\n", executable)
+	if err := Apply(executable); !errors.Is(err, ErrPaused) {
+		return err
+	}
+	log.Printf("Window management is paused, %s is applied after resuming\n", executable)
+	return nil
+}
+
+// Apply applies the settings of the managed app to its main window right away, regardless of its apply_on triggers.
+//
+// Returns an error if the executable is not managed, not running, window management is paused or the settings could
+// not be applied.
+func Apply(executable string) error {
+	if !isManaged(executable) {
+		return fmt.Errorf("%s: %w", executable, errNotManaged)
+	}
+	if pausing.paused() {
+		return ErrPaused
+	}
+	return moveWindow(context.Background(), executable)
+}
+
+// ApplyFocused applies the settings of the managed app owning the focused window, see Apply.
+func ApplyFocused() error {
+	_, executable, err := focusedApp()
+	if err != nil {
+		return err
+	}
+	return Apply(executable)
+}
+
+// Restore puts the windows of the executable FocusFrame changed back into their original state. Unlike Unmanage, the
+// app stays managed and its settings are applied again on the next trigger.
+func Restore(executable string) {
+	RestoreWindows(journaledWindows(executable))
+}
+
+// journaledWindows returns the original state of all windows of the executable recorded in the journal.
+func journaledWindows(executable string) []OriginalState {
+	var windows []OriginalState
+	for _, state := range journal.Windows() {
+		if state.Executable == executable {
+			windows = append(windows, state)
+		}
+	}
+	return windows
+}
+
+// CaptureGeometry saves the current position and size of the focused window as the dimensions of the active layout of
+// its app, so the app is put there from then on.
+//
+// Returns either the executable of the app or an error if the focused window doesn't belong to a managed app or the
+// config could not be saved.
+func CaptureGeometry() (string, error) {
+	h, executable, err := focusedApp()
+	if err != nil {
+		return "", err
+	}
+	if !isManaged(executable) {
+		return "", fmt.Errorf("%s: %w", executable, errNotManaged)
+	}
+	info, err := backend.Info(h)
+	if err != nil {
+		return "", err
+	}
+
+	ws := config.GetWindowSettings(executable)
+	r := measuredRect(info, ws.SizeMode)
+	ws.OffsetX, ws.OffsetY, ws.Width, ws.Height = r.Left, r.Top, r.Width(), r.Height()
+	if err := config.SetDimensions(executable, ws); err != nil {
+		return "", err
+	}
+	log.Printf("Captured geometry %+v of %s\n", r, executable)
+	return executable, nil
+}
+
+// MoveToNextMonitor moves the focused window to the next monitor, keeping its position relative to the work area.
+// Maximized windows are maximized on the next monitor.
+func MoveToNextMonitor() error {
+	h := backend.Foreground()
+	if h == 0 {
+		return fmt.Errorf("%w: no window has focus", ErrWindowNotFound)
+	}
+	info, err := backend.Info(h)
+	if err != nil {
+		return err
+	}
+	monitors, err := backend.Monitors()
+	if err != nil {
+		return err
+	}
+	if len(monitors) < 2 {
+		return errors.New("there is no other monitor to move the window to")
+	}
+
+	if info.Maximized {
+		if err := backend.SetShowState(h, ShowNormal); err != nil {
+			return err
+		}
+		if info, err = backend.Info(h); err != nil {
+			return err
+		}
+		defer func() {
+			if err := backend.SetShowState(h, ShowMaximized); err != nil {
+				log.Printf("Error maximizing window %#x: %v\n", h, err)
+			}
+		}()
+	}
+
+	next := nextMonitor(monitors, info.Monitor)
+	target := placeOnMonitor(measuredRect(info, config.SizeModeFrame), info.Monitor, next)
+	return backend.SetRect(h, outerRect(info, target, config.SizeModeFrame))
+}
+
+// nextMonitor returns the monitor following current from left to right and top to bottom, wrapping around after the
+// last one.
+func nextMonitor(monitors []Monitor, current Monitor) Monitor {
+	sorted := sortedMonitors(monitors)
+	i := slices.IndexFunc(sorted, func(m Monitor) bool { return m.Rect == current.Rect })
+	return sorted[(i+1)%len(sorted)]
+}
+
+// Monitors returns the monitors in the order they are counted in the config, the monitor with number n is at index n-1.
+//
+// Returns either the monitors or an error if they could not be enumerated.
+func Monitors() ([]Monitor, error) {
+	monitors, err := backend.Monitors()
+	if err != nil {
+		return nil, err
+	}
+	return sortedMonitors(monitors), nil
+}
+
+// sortedMonitors returns the monitors from left to right and top to bottom, which is the order they are counted in.
+func sortedMonitors(monitors []Monitor) []Monitor {
+	sorted := slices.Clone(monitors)
+	slices.SortFunc(sorted, func(a, b Monitor) int {
+		if a.Rect.Left != b.Rect.Left {
+			return a.Rect.Left - b.Rect.Left
+		}
+		return a.Rect.Top - b.Rect.Top
+	})
+	return sorted
+}
+
+// placeOnMonitor returns r moved from one monitor to another, keeping its offset to the work area. It is shrunk and
+// shifted as needed to fit the work area of the other monitor.
+func placeOnMonitor(r Rect, from Monitor, to Monitor) Rect {
+	area := to.WorkArea
+	if area.Empty() {
+		area = to.Rect
+	}
+	width, height := min(r.Width(), area.Width()), min(r.Height(), area.Height())
+
+	left := area.Left + r.Left - from.WorkArea.Left
+	top := area.Top + r.Top - from.WorkArea.Top
+	left = max(area.Left, min(left, area.Right-width))
+	top = max(area.Top, min(top, area.Bottom-height))
+	return Rect{Left: left, Top: top, Right: left + width, Bottom: top + height}
+}
@@ -0,0 +1,86 @@
+package window
+
+import (
+	"errors"
+	"testing"
+)
+
+var (
+	left  = Monitor{Handle: 1, Rect: Rect{Right: 1920, Bottom: 1080}, WorkArea: Rect{Right: 1920, Bottom: 1040}, Primary: true}
+	right = Monitor{Handle: 2, Rect: Rect{Left: 1920, Right: 3200, Bottom: 1024}, WorkArea: Rect{Left: 1920, Right: 3200, Bottom: 1024}}
+)
+
+func TestNextMonitor(t *testing.T) {
+	monitors := []Monitor{right, left}
+	if next := nextMonitor(monitors, left); next.Handle != right.Handle {
+		t.Fatalf("Expected the right monitor, got %+v", next)
+	}
+	if next := nextMonitor(monitors, right); next.Handle != left.Handle {
+		t.Fatalf("Expected to wrap around to the left monitor, got %+v", next)
+	}
+}
+
+func TestPlaceOnMonitor(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Rect
+		want Rect
+	}{
+		{"offset kept", Rect{Left: 100, Top: 50, Right: 900, Bottom: 650}, Rect{Left: 2020, Top: 50, Right: 2820, Bottom: 650}},
+		{"shifted to fit", Rect{Left: 1000, Top: 0, Right: 1800, Bottom: 600}, Rect{Left: 2400, Top: 0, Right: 3200, Bottom: 600}},
+		{"shrunk to fit", Rect{Right: 1920, Bottom: 1040}, Rect{Left: 1920, Right: 3200, Bottom: 1024}},
+	}
+
+	for _, test := range tests {
+		if got := placeOnMonitor(test.r, left, right); got != test.want {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.want, got)
+		}
+	}
+}
+
+func TestMoveToNextMonitor(t *testing.T) {
+	fake := NewFake(Info{
+		Handle:  1,
+		Visible: true,
+		Rect:    Rect{Left: 93, Top: 100, Right: 907, Bottom: 707},
+		Frame:   Rect{Left: 100, Top: 100, Right: 900, Bottom: 700},
+		Monitor: left,
+	})
+	fake.SetMonitors(left, right)
+	fake.SetForeground(1)
+	useBackend(t, fake)
+
+	if err := MoveToNextMonitor(); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := fake.Info(1)
+	if info.Frame != (Rect{Left: 2020, Top: 100, Right: 2820, Bottom: 700}) || info.Monitor.Handle != right.Handle {
+		t.Fatalf("Expected the frame to be moved to the right monitor, got %+v on %+v", info.Frame, info.Monitor)
+	}
+
+	fake.SetMonitors(left)
+	if err := MoveToNextMonitor(); err == nil {
+		t.Fatal("Expected an error without another monitor")
+	}
+	fake.SetForeground(0)
+	if err := MoveToNextMonitor(); !errors.Is(err, ErrWindowNotFound) {
+		t.Fatalf("Expected ErrWindowNotFound without focus, got %v", err)
+	}
+}
+
+func TestApplyNotManaged(t *testing.T) {
+	useApps(t)
+	if err := Apply("Game.exe"); !errors.Is(err, errNotManaged) {
+		t.Fatalf("Expected errNotManaged, got %v", err)
+	}
+}
+
+func TestMonitorID(t *testing.T) {
+	id := monitorID(`\\?\DISPLAY#DELA026#5&10a58c63&0&UID16777488#{e6f07b5f-ee97-4a90-b076-33f57bf4eaa7}`)
+	if id != "DELA026#5&10a58c63&0&UID16777488" {
+		t.Fatalf("Unexpected monitor ID %q", id)
+	}
+	if id := monitorID("DISPLAY1"); id != "" {
+		t.Fatalf("Expected no ID for an unexpected device name, got %q", id)
+	}
+}
@@ -0,0 +1,180 @@
+package window
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// companions is the state of the companion windows shrunk beside the focused managed app.
+var companions = struct {
+	sync.Mutex
+	// owner is the window of the managed app the companions are shrunk for, 0 if none are
+	owner Handle
+	// shrunk is the state of the companion windows before they were shrunk
+	shrunk []OriginalState
+}{}
+
+// companionRect returns where the visible frame of a companion window goes in the free area beside a managed app. The
+// configured size is scaled down to fit the area keeping its aspect ratio.
+//
+// Returns an error if the corner is unknown or the area has no room for the window.
+func companionRect(area Rect, c config.Companion) (Rect, error) {
+	width, height := c.Width, c.Height
+	if width <= 0 || height <= 0 {
+		width, height = config.DefaultCompanionWidth, config.DefaultCompanionHeight
+	}
+	free := Rect{Left: area.Left + c.Margin, Top: area.Top + c.Margin, Right: area.Right - c.Margin, Bottom: area.Bottom - c.Margin}
+	if free.Width() <= 0 || free.Height() <= 0 {
+		return Rect{}, fmt.Errorf("no room for %s", c.Executable)
+	}
+	if width > free.Width() {
+		width, height = free.Width(), height*free.Width()/width
+	}
+	if height > free.Height() {
+		width, height = width*free.Height()/height, free.Height()
+	}
+	if width <= 0 || height <= 0 {
+		return Rect{}, fmt.Errorf("no room for %s", c.Executable)
+	}
+
+	left, top := free.Left, free.Top
+	switch c.Corner {
+	case config.CornerTopLeft:
+	case config.CornerTopRight:
+		left = free.Right - width
+	case config.CornerBottomLeft:
+		top = free.Bottom - height
+	case config.CornerBottomRight, "":
+		left, top = free.Right-width, free.Bottom-height
+	default:
+		return Rect{}, fmt.Errorf("unknown corner %q of %s, expected %s, %s, %s or %s", c.Corner, c.Executable,
+			config.CornerTopLeft, config.CornerTopRight, config.CornerBottomLeft, config.CornerBottomRight)
+	}
+	return Rect{Left: left, Top: top, Right: left + width, Bottom: top + height}, nil
+}
+
+// sideArea returns the part of the work area to the left or the right of the frame of the window.
+func sideArea(info Info, left bool) Rect {
+	area := info.Monitor.WorkArea
+	if area.Empty() {
+		area = info.Monitor.Rect
+	}
+	frame := measuredRect(info, config.SizeModeFrame)
+	if left {
+		area.Right = min(area.Right, frame.Left)
+	} else {
+		area.Left = max(area.Left, frame.Right)
+	}
+	return area
+}
+
+// showCompanions shrinks the companion windows of the managed app beside its window h and keeps them above other
+// windows, unless they already are. Companions which are not running or minimized are left alone.
+func showCompanions(ctx context.Context, h Handle, executable string) {
+	app, _ := config.GetManagedApp(executable)
+	if len(app.Companions) == 0 {
+		return
+	}
+
+	companions.Lock()
+	defer companions.Unlock()
+	if companions.owner == h {
+		return
+	}
+	info, err := backend.Info(h)
+	if err != nil {
+		log.Printf("Error showing the companions of %s: %v\n", executable, err)
+		return
+	}
+
+	companions.owner = h
+	for _, c := range app.Companions {
+		state, err := shrinkCompanion(ctx, info, c)
+		if err != nil {
+			log.Printf("Error showing companion %s of %s: %v\n", c.Executable, executable, err)
+		}
+		if state.Handle != 0 {
+			companions.shrunk = append(companions.shrunk, state)
+		}
+	}
+}
+
+// shrinkCompanion moves the window of the companion into its corner beside the window of the managed app.
+//
+// Returns the state of the window before it was changed, without a handle if it wasn't changed, and an error if the
+// companion has no window or it could not be moved.
+func shrinkCompanion(ctx context.Context, owner Info, c config.Companion) (OriginalState, error) {
+	r, err := companionRect(sideArea(owner, c.Corner == config.CornerTopLeft || c.Corner == config.CornerBottomLeft), c)
+	if err != nil {
+		return OriginalState{}, err
+	}
+	pid, err := processIDOfExecutable(c.Executable)
+	if err != nil {
+		return OriginalState{}, err
+	}
+	h := mainWindowOf(pid)
+	if h == 0 {
+		return OriginalState{}, fmt.Errorf("%w for process %d of %s", ErrWindowNotFound, pid, c.Executable)
+	}
+	info, err := backend.Info(h)
+	if err != nil || info.Minimized {
+		return OriginalState{}, err
+	}
+
+	state := originalState(info, c.Executable)
+	// a managed companion must not be moved back to its own dimensions
+	enforcement.forget(h)
+	if info.Maximized {
+		if err := backend.SetShowState(h, ShowNormal); err != nil {
+			return state, err
+		}
+	}
+	if err := SetGeometry(ctx, h, config.WindowSettings{
+		Width:    r.Width(),
+		Height:   r.Height(),
+		OffsetX:  r.Left,
+		OffsetY:  r.Top,
+		SizeMode: config.SizeModeFrame,
+	}); err != nil {
+		return state, err
+	}
+	log.Printf("Showing companion %s at %v\n", c.Executable, r)
+	return state, backend.SetTopmost(h, true)
+}
+
+// isCompanion reports whether the window is a companion shrunk beside the focused managed app.
+func isCompanion(h Handle) bool {
+	companions.Lock()
+	defer companions.Unlock()
+	return slices.ContainsFunc(companions.shrunk, func(s OriginalState) bool { return s.Handle == h })
+}
+
+// hideCompanions puts the companion windows back into the state they had before they were shrunk, unless the window
+// that got focus is the managed app they were shrunk for or one of the companions themselves.
+func hideCompanions(focused Handle) {
+	if isCompanion(focused) {
+		return
+	}
+	companions.Lock()
+	defer companions.Unlock()
+	if companions.owner == 0 || focused == companions.owner {
+		return
+	}
+
+	for _, state := range companions.shrunk {
+		err := restoreState(state)
+		if err == nil {
+			err = backend.SetTopmost(state.Handle, state.ExStyle&WS_EX_TOPMOST != 0)
+		}
+		if err != nil && !errors.Is(err, ErrWindowNotFound) {
+			log.Printf("Error restoring companion %s: %v\n", state.Executable, err)
+		}
+	}
+	companions.owner, companions.shrunk = 0, nil
+}
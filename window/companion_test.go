@@ -0,0 +1,88 @@
+package window
+
+import (
+	"context"
+	"testing"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+func TestCompanionRect(t *testing.T) {
+	area := Rect{Left: 1600, Right: 1920, Bottom: 1040}
+	tests := []struct {
+		companion config.Companion
+		want      Rect
+	}{
+		{config.Companion{Width: 320, Height: 180}, Rect{Left: 1600, Top: 860, Right: 1920, Bottom: 1040}},
+		{config.Companion{Corner: config.CornerTopLeft, Width: 200, Height: 100, Margin: 10}, Rect{Left: 1610, Top: 10, Right: 1810, Bottom: 110}},
+		{config.Companion{Corner: config.CornerTopRight, Width: 200, Height: 100}, Rect{Left: 1720, Right: 1920, Bottom: 100}},
+		{config.Companion{Corner: config.CornerBottomLeft, Width: 200, Height: 100}, Rect{Left: 1600, Top: 940, Right: 1800, Bottom: 1040}},
+		// the default size is too wide and shrunk keeping its aspect ratio
+		{config.Companion{Margin: 10}, Rect{Left: 1610, Top: 862, Right: 1910, Bottom: 1030}},
+	}
+
+	for _, test := range tests {
+		got, err := companionRect(area, test.companion)
+		if err != nil {
+			t.Errorf("%+v: %v", test.companion, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%+v: expected %v, got %v", test.companion, test.want, got)
+		}
+	}
+
+	if _, err := companionRect(area, config.Companion{Corner: "middle"}); err == nil {
+		t.Error("Expected an error for an unknown corner")
+	}
+	if _, err := companionRect(Rect{Left: 1920, Right: 1920, Bottom: 1040}, config.Companion{}); err == nil {
+		t.Error("Expected an error for an area without room")
+	}
+}
+
+func TestCompanions(t *testing.T) {
+	gameApp := game(config.StateSettings{})
+	gameApp.Dimensions = config.WindowSettings{Width: 1280, Height: 1040, OffsetX: 320}
+	gameApp.Companions = []config.Companion{{Executable: "Chat.exe", Corner: config.CornerTopLeft, Width: 320, Height: 180}}
+	useApps(t, gameApp)
+
+	chatRect := Rect{Left: 2000, Top: 100, Right: 2800, Bottom: 700}
+	fake := NewFake(
+		Info{Handle: 1, PID: 10, Title: "Game", Visible: true, Rect: Rect{Left: 100, Top: 100, Right: 900, Bottom: 700}, Monitor: left},
+		Info{Handle: 2, PID: 20, Title: "Chat", Visible: true, Rect: chatRect, Monitor: right},
+		Info{Handle: 3, PID: 30, Title: "Explorer", Visible: true, Rect: Rect{Right: 400, Bottom: 300}, Monitor: left},
+	)
+	fake.SetMonitors(left, right)
+	useBackend(t, fake)
+	useIndex(t, NewIndex((&executables{names: map[uint32]string{10: "Game.exe", 20: "Chat.exe", 30: "explorer.exe"}}).resolve))
+	for _, h := range []Handle{1, 2, 3} {
+		info, _ := fake.Info(h)
+		index.Add(info)
+	}
+	useMainWindows(t)
+	useJournal(t, openJournal(t, ""))
+	t.Cleanup(func() { hideCompanions(0) })
+
+	if err := applyForeground(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	chat, _ := fake.Info(2)
+	if chat.Rect != (Rect{Right: 320, Bottom: 180}) || chat.ExStyle&WS_EX_TOPMOST == 0 {
+		t.Fatalf("Expected the chat to be shrunk into the top left corner beside the game and topmost, got %+v", chat)
+	}
+
+	// clicking the chat doesn't put it back
+	if err := applyForeground(context.Background(), 2); err != nil {
+		t.Fatal(err)
+	}
+	if chat, _ := fake.Info(2); chat.Rect != (Rect{Right: 320, Bottom: 180}) {
+		t.Fatalf("Expected the chat to stay beside the game while it has focus, got %+v", chat.Rect)
+	}
+
+	if err := applyForeground(context.Background(), 3); err != nil {
+		t.Fatal(err)
+	}
+	if chat, _ := fake.Info(2); chat.Rect != chatRect || chat.ExStyle&WS_EX_TOPMOST != 0 {
+		t.Fatalf("Expected the chat to return when the game lost focus, got %+v", chat)
+	}
+}
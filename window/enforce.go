@@ -0,0 +1,209 @@
+package window
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// enforcement re-applies the settings of windows whose app has enforce mode enabled.
+var enforcement *enforcer
+
+func init() {
+	// set up in init since applying the settings refers back to enforcement
+	enforcement = newEnforcer(config.GetEnforceSettings, scheduleEnforce)
+}
+
+// scheduleEnforce re-applies the settings of the window once the delay has passed. No worker waits for the delay, so
+// jobs scheduled for the window in the meantime don't queue up behind it.
+func scheduleEnforce(h Handle, executable string, delay time.Duration) {
+	time.AfterFunc(delay, func() {
+		// the window might have been restored in the meantime
+		if !enforcement.watching(h) || pausing.paused() {
+			return
+		}
+		enforcing.Schedule(Job{
+			Handle: h,
+			Name:   "enforce",
+			Run: func(ctx context.Context) error {
+				if !enforcement.watching(h) || pausing.paused() {
+					return nil
+				}
+				return applySettings(ctx, h, executable)
+			},
+		})
+	})
+}
+
+// enforcer keeps windows at the geometry FocusFrame applied to them.
+//
+// When an enforced window is moved or resized by its app, the settings are re-applied after a delay which doubles
+// with every attempt. If the app keeps fighting back, the enforcer gives up on the window until it is applied again.
+// Moving or resizing the window by the user is accepted, unless the app locks its windows.
+type enforcer struct {
+	mu       sync.Mutex
+	windows  map[Handle]*enforcedWindow
+	settings func(executable string) config.EnforceSettings
+	reapply  func(h Handle, executable string, delay time.Duration)
+	now      func() time.Time
+}
+
+type enforcedWindow struct {
+	executable string
+	settings   config.EnforceSettings
+	expected   Rect        // the rect of the window after it was applied
+	attempts   []time.Time // re-applies within the loop detection window
+	applying   bool        // location changes are caused by FocusFrame itself
+	gaveUp     bool
+	dragging   bool
+	dragEnded  time.Time
+}
+
+func newEnforcer(settings func(executable string) config.EnforceSettings, reapply func(h Handle, executable string, delay time.Duration)) *enforcer {
+	return &enforcer{
+		windows:  make(map[Handle]*enforcedWindow),
+		settings: settings,
+		reapply:  reapply,
+		now:      time.Now,
+	}
+}
+
+// watching reports whether location changes of the window are relevant.
+func (e *enforcer) watching(h Handle) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, ok := e.windows[h]
+	return ok
+}
+
+// reset forgets all re-applies of the window, so an enforcer that gave up on it starts over.
+func (e *enforcer) reset(h Handle) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if w, ok := e.windows[h]; ok {
+		w.attempts = nil
+		w.gaveUp = false
+	}
+}
+
+// applying marks the window as being applied to, its location changes are ignored until applied is called.
+func (e *enforcer) applying(h Handle) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if w, ok := e.windows[h]; ok {
+		w.applying = true
+	}
+}
+
+// applied starts enforcing the current geometry of the window if enforce mode is enabled for the executable.
+func (e *enforcer) applied(h Handle, executable string, err error) {
+	settings := e.settings(executable)
+	info, infoErr := backend.Info(h)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !settings.Enabled || infoErr != nil {
+		delete(e.windows, h)
+		return
+	}
+
+	w, ok := e.windows[h]
+	if err != nil {
+		if ok {
+			w.applying = false // keep enforcing the geometry of the last successful apply
+		}
+		return
+	}
+	if !ok {
+		w = &enforcedWindow{}
+		e.windows[h] = w
+	}
+	w.applying = false
+	w.executable = executable
+	w.settings = settings
+	w.expected = info.Rect
+}
+
+// forget stops enforcing the window, e.g. because it was destroyed.
+func (e *enforcer) forget(h Handle) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.windows, h)
+}
+
+// dragStarted is called when the user starts moving or resizing the window.
+func (e *enforcer) dragStarted(h Handle) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if w, ok := e.windows[h]; ok {
+		w.dragging = true
+	}
+}
+
+// dragEnded is called when the user stops moving or resizing the window. Locked windows are re-applied right away.
+func (e *enforcer) dragEnded(h Handle, t time.Time) {
+	e.mu.Lock()
+	w, ok := e.windows[h]
+	if !ok {
+		e.mu.Unlock()
+		return
+	}
+	w.dragging = false
+	w.dragEnded = t
+	lock := w.settings.Lock && !w.gaveUp
+	executable := w.executable
+	e.mu.Unlock()
+
+	if lock {
+		log.Printf("Window %#x of %s is locked, undoing move\n", h, executable)
+		e.reapply(h, executable, 0)
+	}
+}
+
+// check compares the current state of the window reported by an event at time t to the applied geometry and
+// schedules a re-apply if the app changed it.
+func (e *enforcer) check(info Info, t time.Time) {
+	e.mu.Lock()
+	w, ok := e.windows[info.Handle]
+	if !ok || w.applying || w.gaveUp || info.Minimized || info.Rect == w.expected {
+		e.mu.Unlock()
+		return
+	}
+	if w.dragging || !t.After(w.dragEnded) {
+		// the user moved the window, accept the new geometry unless the window is locked
+		if !w.settings.Lock {
+			w.expected = info.Rect
+		}
+		e.mu.Unlock()
+		return
+	}
+
+	now := e.now()
+	window := time.Duration(w.settings.Window)
+	attempts := w.attempts[:0]
+	for _, attempt := range w.attempts {
+		if now.Sub(attempt) < window {
+			attempts = append(attempts, attempt)
+		}
+	}
+	w.attempts = attempts
+
+	executable := w.executable
+	if len(w.attempts) >= w.settings.MaxAttempts {
+		w.gaveUp = true
+		e.mu.Unlock()
+		log.Printf("Giving up on enforcing window %#x of %s, it was changed %d times within %s\n", info.Handle, executable, len(attempts), window)
+		return
+	}
+
+	delay := time.Duration(w.settings.Backoff) << len(w.attempts)
+	w.attempts = append(w.attempts, now)
+	e.mu.Unlock()
+
+	log.Printf("Window %#x of %s changed its geometry to %+v, re-applying in %s\n", info.Handle, executable, info.Rect, delay)
+	e.reapply(info.Handle, executable, delay)
+}
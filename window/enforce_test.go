@@ -0,0 +1,204 @@
+package window
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+type reapply struct {
+	handle Handle
+	delay  time.Duration
+}
+
+// recordReapply returns an enforcer for Game.exe with the given settings which records its re-applies instead of
+// scheduling them, and a clock that can be advanced.
+func recordReapply(settings config.EnforceSettings) (*enforcer, *[]reapply, *time.Time) {
+	var reapplies []reapply
+	now := time.Unix(1000, 0)
+	e := newEnforcer(func(executable string) config.EnforceSettings {
+		if executable != "Game.exe" {
+			return config.EnforceSettings{}
+		}
+		return settings
+	}, func(h Handle, executable string, delay time.Duration) {
+		reapplies = append(reapplies, reapply{h, delay})
+	})
+	e.now = func() time.Time { return now }
+	return e, &reapplies, &now
+}
+
+func enforceSettings() config.EnforceSettings {
+	return config.EnforceSettings{
+		Enabled:     true,
+		MaxAttempts: 3,
+		Backoff:     config.Duration(100 * time.Millisecond),
+		Window:      config.Duration(time.Minute),
+	}
+}
+
+var appliedRect = Rect{Right: 1920, Bottom: 1080}
+
+func TestEnforcerBackoff(t *testing.T) {
+	fake := NewFake(Info{Handle: 1, Visible: true, Rect: appliedRect})
+	useBackend(t, fake)
+	e, reapplies, now := recordReapply(enforceSettings())
+	e.applied(1, "Game.exe", nil)
+
+	// the window moving to the applied rect is caused by FocusFrame itself
+	e.check(Info{Handle: 1, Rect: appliedRect}, *now)
+	if len(*reapplies) != 0 {
+		t.Fatalf("Expected no re-apply for an unchanged window, got %v", *reapplies)
+	}
+
+	shrunk := Rect{Right: 1280, Bottom: 720}
+	for i, expected := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		*now = now.Add(time.Second)
+		e.check(Info{Handle: 1, Rect: shrunk}, *now)
+		if len(*reapplies) != i+1 || (*reapplies)[i].delay != expected {
+			t.Fatalf("Expected re-apply %d after %s, got %v", i+1, expected, *reapplies)
+		}
+	}
+
+	// the app keeps fighting back, give up
+	*now = now.Add(time.Second)
+	e.check(Info{Handle: 1, Rect: shrunk}, *now)
+	if len(*reapplies) != 3 {
+		t.Fatalf("Expected the enforcer to give up, got %v", *reapplies)
+	}
+
+	// applying the window again starts over
+	e.reset(1)
+	e.check(Info{Handle: 1, Rect: shrunk}, *now)
+	if len(*reapplies) != 4 || (*reapplies)[3].delay != 100*time.Millisecond {
+		t.Fatalf("Expected a re-apply after reset, got %v", *reapplies)
+	}
+}
+
+func TestEnforcerAttemptsExpire(t *testing.T) {
+	useBackend(t, NewFake(Info{Handle: 1, Visible: true, Rect: appliedRect}))
+	e, reapplies, now := recordReapply(enforceSettings())
+	e.applied(1, "Game.exe", nil)
+
+	// an app that changes its window once in a while, e.g. after changing its settings, is no loop
+	for i := 0; i < 5; i++ {
+		*now = now.Add(time.Minute)
+		e.check(Info{Handle: 1, Rect: Rect{Right: 800, Bottom: 600}}, *now)
+	}
+	if len(*reapplies) != 5 {
+		t.Fatalf("Expected 5 re-applies, got %v", *reapplies)
+	}
+	for _, r := range *reapplies {
+		if r.delay != 100*time.Millisecond {
+			t.Fatalf("Expected the backoff to start over, got %v", *reapplies)
+		}
+	}
+}
+
+func TestEnforcerIgnoresOwnChanges(t *testing.T) {
+	useBackend(t, NewFake(Info{Handle: 1, Visible: true, Rect: appliedRect}))
+	e, reapplies, now := recordReapply(enforceSettings())
+	e.applied(1, "Game.exe", nil)
+
+	e.applying(1)
+	e.check(Info{Handle: 1, Rect: Rect{Right: 800, Bottom: 600}}, *now)
+	e.check(Info{Handle: 1, Rect: appliedRect, Minimized: true}, *now)
+	e.applied(1, "Game.exe", errors.New("window is not ready"))
+
+	if len(*reapplies) != 0 {
+		t.Fatalf("Expected no re-apply while applying, got %v", *reapplies)
+	}
+	if !e.watching(1) {
+		t.Fatal("Expected a failed re-apply to keep enforcing the window")
+	}
+}
+
+func TestEnforcerDisabled(t *testing.T) {
+	useBackend(t, NewFake(
+		Info{Handle: 1, Visible: true, Rect: appliedRect},
+		Info{Handle: 2, Visible: true, Rect: appliedRect},
+	))
+	e, _, _ := recordReapply(enforceSettings())
+	e.applied(1, "Other.exe", nil)
+	e.applied(2, "Game.exe", errors.New("window is not ready"))
+
+	if e.watching(1) || e.watching(2) {
+		t.Fatal("Expected only windows of enforced apps that were applied to be watched")
+	}
+}
+
+func TestEnforcerDrag(t *testing.T) {
+	useBackend(t, NewFake(Info{Handle: 1, Visible: true, Rect: appliedRect}))
+	e, reapplies, now := recordReapply(enforceSettings())
+	e.applied(1, "Game.exe", nil)
+
+	moved := appliedRect
+	moved.Left, moved.Right = 100, 2020
+	e.dragStarted(1)
+	e.check(Info{Handle: 1, Rect: moved}, *now)
+	e.dragEnded(1, *now)
+	// the event of the last move arrives after the drag ended
+	e.check(Info{Handle: 1, Rect: moved}, *now)
+	e.check(Info{Handle: 1, Rect: moved}, now.Add(time.Second))
+
+	if len(*reapplies) != 0 {
+		t.Fatalf("Expected the user to be able to move the window, got %v", *reapplies)
+	}
+}
+
+func TestEnforcerLock(t *testing.T) {
+	useBackend(t, NewFake(Info{Handle: 1, Visible: true, Rect: appliedRect}))
+	settings := enforceSettings()
+	settings.Lock = true
+	e, reapplies, now := recordReapply(settings)
+	e.applied(1, "Game.exe", nil)
+
+	e.dragStarted(1)
+	e.check(Info{Handle: 1, Rect: Rect{Left: 100, Right: 2020, Bottom: 1080}}, *now)
+	e.dragEnded(1, *now)
+
+	if len(*reapplies) != 1 || (*reapplies)[0].delay != 0 {
+		t.Fatalf("Expected the locked window to be re-applied right after the drag, got %v", *reapplies)
+	}
+}
+
+func TestEnforceKeepsFocusJob(t *testing.T) {
+	gameApp := game(config.StateSettings{})
+	gameApp.Enforce = enforceSettings()
+	useApps(t, gameApp)
+	fake := NewFake(Info{Handle: 1, PID: 10, Title: "Game", Visible: true, Style: WS_CAPTION, Rect: Rect{Right: 800, Bottom: 600}})
+	useBackend(t, fake)
+	useJournal(t, openJournal(t, ""))
+	useScheduler(t, NewScheduler(applyTimeout, 10*time.Millisecond))
+	previous := enforcing
+	enforcing = NewScheduler(applyTimeout, 0)
+	t.Cleanup(func() { enforcing = previous })
+	enforcement.applied(1, "Game.exe", nil)
+	t.Cleanup(func() { enforcement.forget(1) })
+
+	// the re-apply becomes due while the focus job of the same window is still waiting
+	focused := make(chan struct{})
+	scheduler.Schedule(Job{Handle: 1, Name: "focus", Run: func(ctx context.Context) error {
+		close(focused)
+		return nil
+	}})
+	scheduleEnforce(1, "Game.exe", 0)
+
+	select {
+	case <-focused:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the focus job to run")
+	}
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(5 * time.Millisecond) {
+		if info, _ := fake.Info(1); info.Rect == appliedRect {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the settings to be re-applied")
+		}
+	}
+	enforcing.Wait()
+}
@@ -0,0 +1,46 @@
+package window
+
+import (
+	"time"
+)
+
+// EventKind describes what happened to a window.
+type EventKind int
+
+const (
+	EventForeground      EventKind = iota // The window received focus
+	EventShown                            // The window was created or became visible
+	EventDestroyed                        // The window was destroyed
+	EventNameChanged                      // The title of the window changed
+	EventLocationChanged                  // The window was moved or resized
+	EventMoveSizeStart                    // The user started dragging or resizing the window
+	EventMoveSizeEnd                      // The user stopped dragging or resizing the window
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventForeground:
+		return "foreground"
+	case EventShown:
+		return "shown"
+	case EventDestroyed:
+		return "destroyed"
+	case EventNameChanged:
+		return "name changed"
+	case EventLocationChanged:
+		return "location changed"
+	case EventMoveSizeStart:
+		return "move/size start"
+	case EventMoveSizeEnd:
+		return "move/size end"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a change of a top-level window reported by the windowing system.
+type Event struct {
+	Kind   EventKind
+	Handle Handle
+	Time   time.Time
+}
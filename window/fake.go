@@ -0,0 +1,332 @@
+package window
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Fake is an in-memory Backend simulating a desktop, intended for tests.
+type Fake struct {
+	mu         sync.Mutex
+	windows    []Info // z-order, topmost window first
+	foreground Handle
+	hung       map[Handle]bool
+	limits     map[Handle]func(r Rect) Rect
+	monitors   []Monitor
+	// restoreMaximized are the minimized windows which were maximized before
+	restoreMaximized map[Handle]bool
+	backdrops        map[Handle]Color
+	lastBackdrop     Handle
+}
+
+// firstBackdrop is the handle of the first backdrop window of a Fake, far above the handles tests use.
+const firstBackdrop Handle = 0xbd000
+
+// NewFake returns a Fake desktop containing the given windows, the first window is the topmost one.
+func NewFake(windows ...Info) *Fake {
+	return &Fake{windows: windows}
+}
+
+// Add places a new window on top of all other windows.
+func (f *Fake) Add(info Info) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.windows = append([]Info{info}, f.windows...)
+}
+
+// Update changes the state of an existing window.
+func (f *Fake) Update(h Handle, update func(info *Info)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if i := f.index(h); i >= 0 {
+		update(&f.windows[i])
+	}
+}
+
+// Remove destroys the window with the given handle.
+func (f *Fake) Remove(h Handle) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if i := f.index(h); i >= 0 {
+		f.windows = append(f.windows[:i], f.windows[i+1:]...)
+	}
+	if f.foreground == h {
+		f.foreground = 0
+	}
+}
+
+// SetForeground gives focus to the window with the given handle.
+func (f *Fake) SetForeground(h Handle) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.foreground = h
+}
+
+// SetHung marks the window as (not) responding to messages.
+func (f *Fake) SetHung(h Handle, hung bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.hung == nil {
+		f.hung = make(map[Handle]bool)
+	}
+	f.hung[h] = hung
+}
+
+// SetLimit makes the window's app adjust every rect the window is moved to, e.g. to clamp its size.
+func (f *Fake) SetLimit(h Handle, limit func(r Rect) Rect) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.limits == nil {
+		f.limits = make(map[Handle]func(r Rect) Rect)
+	}
+	f.limits[h] = limit
+}
+
+// SetMonitors replaces the monitors of the desktop. Windows moved by SetRect are assigned the monitor containing the
+// center of their new rect.
+func (f *Fake) SetMonitors(monitors ...Monitor) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.monitors = monitors
+}
+
+func (f *Fake) Monitors() ([]Monitor, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Monitor(nil), f.monitors...), nil
+}
+
+func (f *Fake) Windows() ([]Info, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var windows []Info
+	for _, info := range f.windows {
+		if info.Visible {
+			windows = append(windows, withNormalRect(info))
+		}
+	}
+	return windows, nil
+}
+
+func (f *Fake) Info(h Handle) (Info, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if i := f.index(h); i >= 0 {
+		return withNormalRect(f.windows[i]), nil
+	}
+	return Info{}, fmt.Errorf("%w: %#x", ErrWindowNotFound, uintptr(h))
+}
+
+// withNormalRect returns the window with its rect as the normal rect, unless it is minimized or maximized and a normal
+// rect was given.
+func withNormalRect(info Info) Info {
+	if info.NormalRect.Empty() || !info.Minimized && !info.Maximized {
+		info.NormalRect = info.Rect
+	}
+	return info
+}
+
+func (f *Fake) Foreground() Handle {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.foreground
+}
+
+func (f *Fake) Responding(h Handle) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.index(h) >= 0 && !f.hung[h]
+}
+
+// SetRect moves the window. Its frame and client area keep their distance to the edges of the window rect, like
+// the invisible borders and decorations of a real window.
+func (f *Fake) SetRect(h Handle, r Rect) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i := f.index(h)
+	if i < 0 {
+		return fmt.Errorf("%w: %#x", ErrWindowNotFound, uintptr(h))
+	}
+	if limit := f.limits[h]; limit != nil {
+		r = limit(r)
+	}
+
+	info := &f.windows[i]
+	info.Frame = moveInset(info.Frame, info.Rect, r)
+	info.ClientRect = moveInset(info.ClientRect, info.Rect, r)
+	info.Rect = r
+
+	x, y := (r.Left+r.Right)/2, (r.Top+r.Bottom)/2
+	for _, m := range f.monitors {
+		if x >= m.Rect.Left && x < m.Rect.Right && y >= m.Rect.Top && y < m.Rect.Bottom {
+			info.Monitor = m
+		}
+	}
+	return nil
+}
+
+// SetStyle changes the style of the window. Removing the decorations doesn't change the geometry of the window.
+func (f *Fake) SetStyle(h Handle, change StyleChange) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i := f.index(h)
+	if i < 0 {
+		return fmt.Errorf("%w: %#x", ErrWindowNotFound, uintptr(h))
+	}
+	info := &f.windows[i]
+	info.Style, info.ExStyle = change.Apply(info.Style, info.ExStyle)
+	return nil
+}
+
+// SetShowState minimizes, maximizes or restores the window. The geometry of the window doesn't change.
+func (f *Fake) SetShowState(h Handle, state ShowState) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i := f.index(h)
+	if i < 0 {
+		return fmt.Errorf("%w: %#x", ErrWindowNotFound, uintptr(h))
+	}
+	info := &f.windows[i]
+	if f.restoreMaximized == nil {
+		f.restoreMaximized = make(map[Handle]bool)
+	}
+	switch state {
+	case ShowMinimized:
+		if !info.Minimized {
+			f.restoreMaximized[h] = info.Maximized
+		}
+	case ShowRestored:
+		if !info.Minimized {
+			return nil
+		}
+		state = ShowNormal
+		if f.restoreMaximized[h] {
+			state = ShowMaximized
+		}
+	}
+	info.Minimized = state == ShowMinimized
+	info.Maximized = state == ShowMaximized
+	return nil
+}
+
+// SetTopmost sets or clears WS_EX_TOPMOST. A topmost window is moved on top of all other windows.
+func (f *Fake) SetTopmost(h Handle, topmost bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i := f.index(h)
+	if i < 0 {
+		return fmt.Errorf("%w: %#x", ErrWindowNotFound, uintptr(h))
+	}
+	info := f.windows[i]
+	if !topmost {
+		f.windows[i].ExStyle &^= WS_EX_TOPMOST
+		return nil
+	}
+	info.ExStyle |= WS_EX_TOPMOST
+	f.windows = append([]Info{info}, append(f.windows[:i:i], f.windows[i+1:]...)...)
+	return nil
+}
+
+// PlaceBehind moves the window directly behind the window above. It stops being topmost unless above is topmost.
+func (f *Fake) PlaceBehind(h Handle, above Handle) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i, j := f.index(h), f.index(above)
+	if i < 0 || j < 0 {
+		return fmt.Errorf("%w: %#x or %#x", ErrWindowNotFound, uintptr(h), uintptr(above))
+	}
+	info := f.windows[i]
+	if f.windows[j].ExStyle&WS_EX_TOPMOST == 0 {
+		info.ExStyle &^= WS_EX_TOPMOST
+	}
+	f.windows = slices.Delete(f.windows, i, i+1)
+	j = f.index(above)
+	f.windows = slices.Insert(f.windows, j+1, info)
+	return nil
+}
+
+// Activate moves the window on top of all other windows and gives it focus.
+func (f *Fake) Activate(h Handle) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i := f.index(h)
+	if i < 0 {
+		return fmt.Errorf("%w: %#x", ErrWindowNotFound, uintptr(h))
+	}
+	info := f.windows[i]
+	f.windows = slices.Insert(slices.Delete(f.windows, i, i+1), 0, info)
+	f.foreground = h
+	return nil
+}
+
+// ShowBackdrop adds a visible window covering r directly behind the window above.
+func (f *Fake) ShowBackdrop(r Rect, color Color, above Handle) (Handle, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i := f.index(above)
+	if i < 0 {
+		return 0, fmt.Errorf("%w: %#x", ErrWindowNotFound, uintptr(above))
+	}
+	if f.backdrops == nil {
+		f.backdrops = make(map[Handle]Color)
+		f.lastBackdrop = firstBackdrop
+	}
+	f.lastBackdrop++
+	h := f.lastBackdrop
+	f.backdrops[h] = color
+
+	backdrop := Info{Handle: h, Class: "FocusFrameBackdrop", Visible: true, Style: WS_POPUP, ExStyle: WS_EX_NOACTIVATE, Rect: r, Frame: r, ClientRect: r}
+	for _, m := range f.monitors {
+		if m.Rect.Contains(r) {
+			backdrop.Monitor = m
+		}
+	}
+	f.windows = append(f.windows[:i+1], append([]Info{backdrop}, f.windows[i+1:]...)...)
+	return h, nil
+}
+
+// HideBackdrop removes a window created by ShowBackdrop.
+func (f *Fake) HideBackdrop(h Handle) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i := f.index(h)
+	if _, ok := f.backdrops[h]; !ok || i < 0 {
+		return fmt.Errorf("%w: no backdrop %#x", ErrWindowNotFound, uintptr(h))
+	}
+	delete(f.backdrops, h)
+	f.windows = append(f.windows[:i], f.windows[i+1:]...)
+	return nil
+}
+
+// Backdrops returns the color of every backdrop window shown.
+func (f *Fake) Backdrops() map[Handle]Color {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return maps.Clone(f.backdrops)
+}
+
+// moveInset returns inner moved along with its outer rect from one position to another.
+func moveInset(inner, from, to Rect) Rect {
+	if inner == (Rect{}) {
+		return Rect{}
+	}
+	return Rect{
+		Left:   to.Left + inner.Left - from.Left,
+		Top:    to.Top + inner.Top - from.Top,
+		Right:  to.Right - (from.Right - inner.Right),
+		Bottom: to.Bottom - (from.Bottom - inner.Bottom),
+	}
+}
+
+// index returns the position of the window in the z-order or -1 if it doesn't exist. The caller must hold the lock.
+func (f *Fake) index(h Handle) int {
+	for i, info := range f.windows {
+		if info.Handle == h {
+			return i
+		}
+	}
+	return -1
+}
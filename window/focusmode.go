@@ -0,0 +1,120 @@
+package window
+
+import (
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// focusMode is the state of the focus mode of the managed app which has focus.
+var focusMode = struct {
+	sync.Mutex
+	// owner is the window of the app in focus mode, 0 if no app is
+	owner Handle
+	// backdrop is the window covering the monitor behind the owner, 0 if there is none
+	backdrop Handle
+	// minimized are the windows minimized for the owner
+	minimized []Handle
+}{}
+
+// enterFocusMode hides everything else on the monitor of the window h of the managed app, with a backdrop behind it
+// or by minimizing the other windows as configured, unless it already is.
+func enterFocusMode(h Handle, executable string) {
+	app, _ := config.GetManagedApp(executable)
+	fs := app.Focus
+	if fs.Mode == "" {
+		return
+	}
+
+	focusMode.Lock()
+	defer focusMode.Unlock()
+	if focusMode.owner == h {
+		return
+	}
+	info, err := backend.Info(h)
+	if err != nil {
+		log.Printf("Error entering the focus mode of %s: %v\n", executable, err)
+		return
+	}
+	if info.Monitor.Rect.Empty() {
+		log.Printf("Error entering the focus mode of %s: the window is on no monitor\n", executable)
+		return
+	}
+
+	switch fs.Mode {
+	case config.FocusModeBackdrop:
+		color := Color{}
+		if fs.Color != "" {
+			if color, err = ParseColor(fs.Color); err != nil {
+				log.Printf("Invalid backdrop of %s, using black: %v\n", executable, err)
+			}
+		}
+		backdrop, err := backend.ShowBackdrop(info.Monitor.Rect, color, h)
+		if err != nil {
+			log.Printf("Error showing the backdrop of %s: %v\n", executable, err)
+			return
+		}
+		focusMode.backdrop = backdrop
+	case config.FocusModeMinimize:
+		focusMode.minimized = minimizeOthers(info)
+	default:
+		log.Printf("Unknown focus mode %q of %s, expected %s or %s\n", fs.Mode, executable, config.FocusModeBackdrop, config.FocusModeMinimize)
+		return
+	}
+	focusMode.owner = h
+	log.Printf("Entered the focus mode of %s\n", executable)
+}
+
+// minimizeOthers minimizes the windows of other apps on the monitor of the window, except for companions and windows
+// which can't be minimized by the user, like tool windows, dialogs and topmost overlays.
+//
+// Returns the minimized windows.
+func minimizeOthers(owner Info) []Handle {
+	windows, err := backend.Windows()
+	if err != nil {
+		log.Println("Error listing windows:", err)
+		return nil
+	}
+
+	var minimized []Handle
+	for _, w := range windows {
+		if w.PID == owner.PID || w.Monitor.Rect != owner.Monitor.Rect || w.Minimized || w.Cloaked || w.Owner != 0 ||
+			w.Style&WS_MINIMIZEBOX == 0 || w.ExStyle&(WS_EX_TOOLWINDOW|WS_EX_TOPMOST) != 0 || isCompanion(w.Handle) {
+			continue
+		}
+		if err := backend.SetShowState(w.Handle, ShowMinimized); err != nil {
+			log.Printf("Error minimizing window %#x: %v\n", w.Handle, err)
+			continue
+		}
+		minimized = append(minimized, w.Handle)
+	}
+	return minimized
+}
+
+// leaveFocusMode brings back what the focus mode hid, unless the window that got focus is the app in focus mode or one
+// of its companions.
+func leaveFocusMode(focused Handle) {
+	if isCompanion(focused) {
+		return
+	}
+	focusMode.Lock()
+	defer focusMode.Unlock()
+	if focusMode.owner == 0 || focused == focusMode.owner {
+		return
+	}
+
+	if focusMode.backdrop != 0 {
+		if err := backend.HideBackdrop(focusMode.backdrop); err != nil {
+			log.Println("Error hiding backdrop:", err)
+		}
+	}
+	for _, h := range focusMode.minimized {
+		if err := backend.SetShowState(h, ShowRestored); err != nil && !errors.Is(err, ErrWindowNotFound) {
+			log.Printf("Error restoring window %#x: %v\n", h, err)
+		}
+	}
+	focusMode.owner, focusMode.backdrop, focusMode.minimized = 0, 0, nil
+	log.Println("Left focus mode")
+}
@@ -0,0 +1,103 @@
+package window
+
+import (
+	"context"
+	"testing"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// focusDesktop sets up a game in the given focus mode on the left monitor, with other windows on both monitors.
+func focusDesktop(t *testing.T, fs config.FocusSettings, others ...Info) *Fake {
+	t.Helper()
+	gameApp := game(config.StateSettings{})
+	gameApp.Dimensions = config.WindowSettings{Width: 1280, Height: 1040, OffsetX: 320}
+	gameApp.Focus = fs
+	useApps(t, gameApp)
+
+	fake := NewFake(append([]Info{{Handle: 1, PID: 10, Title: "Game", Visible: true, Rect: Rect{Left: 100, Top: 100, Right: 900, Bottom: 700}, Monitor: left}}, others...)...)
+	fake.SetMonitors(left, right)
+	useBackend(t, fake)
+	names := map[uint32]string{10: "Game.exe"}
+	for _, info := range others {
+		names[info.PID] = "Other.exe"
+	}
+	useIndex(t, NewIndex((&executables{names: names}).resolve))
+	index.Add(Info{Handle: 1, PID: 10})
+	useMainWindows(t)
+	useJournal(t, openJournal(t, ""))
+	t.Cleanup(func() { leaveFocusMode(0) })
+	return fake
+}
+
+func TestFocusModeBackdrop(t *testing.T) {
+	fake := focusDesktop(t, config.FocusSettings{Mode: config.FocusModeBackdrop, Color: "#202020"},
+		Info{Handle: 2, PID: 20, Title: "Explorer", Visible: true, Style: WS_MINIMIZEBOX, Monitor: left})
+
+	if err := applyForeground(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	windows, _ := fake.Windows()
+	if len(windows) != 3 || windows[0].Handle != 1 || windows[1].Rect != left.Rect {
+		t.Fatalf("Expected a backdrop covering the monitor right behind the game, got %+v", windows)
+	}
+	if color := fake.Backdrops()[windows[1].Handle]; color != (Color{0x20, 0x20, 0x20}) {
+		t.Fatalf("Expected the backdrop in the configured color, got %v", color)
+	}
+
+	// focusing the game again keeps the backdrop
+	if err := applyForeground(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.Backdrops()) != 1 {
+		t.Fatalf("Expected a single backdrop, got %v", fake.Backdrops())
+	}
+
+	index.Add(Info{Handle: 2, PID: 20})
+	if err := applyForeground(context.Background(), 2); err != nil {
+		t.Fatal(err)
+	}
+	if windows, _ := fake.Windows(); len(windows) != 2 || len(fake.Backdrops()) != 0 {
+		t.Fatalf("Expected the backdrop to be gone after the game lost focus, got %+v", windows)
+	}
+}
+
+func TestFocusModeMinimize(t *testing.T) {
+	fake := focusDesktop(t, config.FocusSettings{Mode: config.FocusModeMinimize},
+		Info{Handle: 2, PID: 20, Title: "Explorer", Visible: true, Style: WS_MINIMIZEBOX, Monitor: left},
+		Info{Handle: 3, PID: 20, Title: "Browser", Visible: true, Style: WS_MINIMIZEBOX, Maximized: true, Monitor: left},
+		Info{Handle: 4, PID: 20, Title: "Palette", Visible: true, Style: WS_MINIMIZEBOX, ExStyle: WS_EX_TOOLWINDOW, Monitor: left},
+		Info{Handle: 5, PID: 20, Title: "Chat", Visible: true, Style: WS_MINIMIZEBOX, Monitor: right},
+	)
+
+	if err := applyForeground(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	for h, minimized := range map[Handle]bool{2: true, 3: true, 4: false, 5: false} {
+		if info, _ := fake.Info(h); info.Minimized != minimized {
+			t.Errorf("Expected window %d to be minimized %v, got %+v", h, minimized, info)
+		}
+	}
+
+	index.Add(Info{Handle: 5, PID: 20})
+	if err := applyForeground(context.Background(), 5); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := fake.Info(2); info.Minimized || info.Maximized {
+		t.Errorf("Expected window 2 to be restored, got %+v", info)
+	}
+	if info, _ := fake.Info(3); info.Minimized || !info.Maximized {
+		t.Errorf("Expected window 3 to be maximized again, got %+v", info)
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	if c, err := ParseColor("#ff8000"); err != nil || c != (Color{R: 0xff, G: 0x80}) {
+		t.Fatalf("Expected orange, got %v, %v", c, err)
+	}
+	for _, s := range []string{"ff8000", "#ff80", "#gg0000", ""} {
+		if _, err := ParseColor(s); err == nil {
+			t.Errorf("Expected an error for %q", s)
+		}
+	}
+}
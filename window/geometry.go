@@ -0,0 +1,129 @@
+package window
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+const (
+	// geometryAttempts is how often FocusFrame tries to get a window to the configured geometry. Some apps resize
+	// their window right after it was moved, which is why a single attempt is not enough (see #47).
+	geometryAttempts = 3
+	// geometryRetryDelay is the pause between two attempts.
+	geometryRetryDelay = 100 * time.Millisecond
+)
+
+// GeometryError reports that a window did not end up with the configured geometry.
+type GeometryError struct {
+	Wanted Rect
+	Got    Rect
+	Reason string
+}
+
+func (e *GeometryError) Error() string {
+	return fmt.Sprintf("window is at %+v instead of %+v: %s", e.Got, e.Wanted, e.Reason)
+}
+
+// wantedRect returns the rect the frame or client area of a window should have according to the settings.
+func wantedRect(ws config.WindowSettings) Rect {
+	return Rect{Left: ws.OffsetX, Top: ws.OffsetY, Right: ws.OffsetX + ws.Width, Bottom: ws.OffsetY + ws.Height}
+}
+
+// measuredRect returns the part of the window the size mode applies to, i.e. its visible frame or its client area.
+func measuredRect(info Info, sizeMode string) Rect {
+	if sizeMode == config.SizeModeClient {
+		return info.ClientRect
+	}
+	if info.Frame.Empty() {
+		return info.Rect
+	}
+	return info.Frame
+}
+
+// outerRect returns the window rect which results in the measured rect of the window being wanted. It compensates
+// for the invisible resize borders Windows adds around the frame and, in client mode, for the caption and borders.
+func outerRect(info Info, wanted Rect, sizeMode string) Rect {
+	inner := measuredRect(info, sizeMode)
+	return Rect{
+		Left:   wanted.Left - (inner.Left - info.Rect.Left),
+		Top:    wanted.Top - (inner.Top - info.Rect.Top),
+		Right:  wanted.Right + (info.Rect.Right - inner.Right),
+		Bottom: wanted.Bottom + (info.Rect.Bottom - inner.Bottom),
+	}
+}
+
+// verifyGeometry compares the measured rect of the window to the wanted one.
+//
+// Returns either nil if they match or a *GeometryError with the most likely reason for the mismatch.
+func verifyGeometry(info Info, wanted Rect, sizeMode string) error {
+	got := measuredRect(info, sizeMode)
+	if got == wanted {
+		return nil
+	}
+
+	monitor := info.Monitor.Rect
+	var reason string
+	switch {
+	case info.Minimized:
+		reason = "the window is minimized"
+	case info.Maximized:
+		reason = "the window is maximized"
+	case got.Width() == wanted.Width() && got.Height() == wanted.Height():
+		reason = "the window was moved to a different position"
+	case !monitor.Empty() && (wanted.Width() > monitor.Width() || wanted.Height() > monitor.Height()) &&
+		got.Width() <= monitor.Width() && got.Height() <= monitor.Height():
+		reason = "the window was limited to the size of its monitor"
+	case got.Width() < wanted.Width() || got.Height() < wanted.Height():
+		reason = "the app limits the maximum size of the window"
+	default:
+		reason = "the app enforces a larger minimum size of the window"
+	}
+	return &GeometryError{Wanted: wanted, Got: got, Reason: reason}
+}
+
+// SetGeometry moves and resizes the window so its visible frame or client area (see config.SizeModeClient) matches
+// the settings, then reads back the result.
+//
+// Returns either nil if the window has the configured geometry, a *GeometryError if it ended up somewhere else, the
+// error of the context if it ended while waiting to retry, or another error if the window could not be moved.
+func SetGeometry(ctx context.Context, h Handle, ws config.WindowSettings) error {
+	wanted := wantedRect(ws)
+
+	info, err := backend.Info(h)
+	if err != nil {
+		return err
+	}
+	if verifyGeometry(info, wanted, ws.SizeMode) == nil {
+		log.Println("Window position and size already correct, no changes needed.")
+		return nil
+	}
+
+	for attempt := 1; ; attempt++ {
+		// the borders are measured again on every attempt since they change with the style of the window
+		if err := backend.SetRect(h, outerRect(info, wanted, ws.SizeMode)); err != nil {
+			return err
+		}
+
+		if info, err = backend.Info(h); err != nil {
+			return err
+		}
+		err = verifyGeometry(info, wanted, ws.SizeMode)
+		if err == nil || attempt == geometryAttempts {
+			return err
+		}
+
+		log.Printf("Retrying to move window %#x, attempt %d of %d: %v\n", h, attempt, geometryAttempts, err)
+		select {
+		case <-time.After(geometryRetryDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if info, err = backend.Info(h); err != nil {
+			return err
+		}
+	}
+}
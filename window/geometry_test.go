@@ -0,0 +1,171 @@
+package window
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// bordered returns a window whose frame has the invisible resize borders of Windows 10 and whose client area lies
+// below a caption.
+func bordered(h Handle, frame Rect) Info {
+	return Info{
+		Handle:     h,
+		Visible:    true,
+		Rect:       Rect{Left: frame.Left - 7, Top: frame.Top, Right: frame.Right + 7, Bottom: frame.Bottom + 7},
+		Frame:      frame,
+		ClientRect: Rect{Left: frame.Left + 1, Top: frame.Top + 31, Right: frame.Right - 1, Bottom: frame.Bottom - 1},
+		Monitor:    Monitor{Rect: Rect{Right: 2560, Bottom: 1440}},
+	}
+}
+
+func windowSettings(sizeMode string) config.WindowSettings {
+	return config.WindowSettings{Width: 1920, Height: 1080, OffsetX: 320, OffsetY: 180, SizeMode: sizeMode}
+}
+
+func TestSetGeometryFrame(t *testing.T) {
+	fake := NewFake(bordered(1, Rect{Right: 800, Bottom: 600}))
+	useBackend(t, fake)
+
+	if err := SetGeometry(context.Background(), 1, windowSettings(config.SizeModeFrame)); err != nil {
+		t.Fatal(err)
+	}
+
+	info, _ := fake.Info(1)
+	if want := (Rect{Left: 320, Top: 180, Right: 2240, Bottom: 1260}); info.Frame != want {
+		t.Fatalf("Expected the visible frame to be %+v, got %+v", want, info.Frame)
+	}
+	if want := (Rect{Left: 313, Top: 180, Right: 2247, Bottom: 1267}); info.Rect != want {
+		t.Fatalf("Expected the invisible borders to lie outside, got %+v", info.Rect)
+	}
+}
+
+func TestSetGeometryClient(t *testing.T) {
+	fake := NewFake(bordered(1, Rect{Right: 800, Bottom: 600}))
+	useBackend(t, fake)
+
+	if err := SetGeometry(context.Background(), 1, windowSettings(config.SizeModeClient)); err != nil {
+		t.Fatal(err)
+	}
+
+	info, _ := fake.Info(1)
+	if want := (Rect{Left: 320, Top: 180, Right: 2240, Bottom: 1260}); info.ClientRect != want {
+		t.Fatalf("Expected the client area to be %+v, got %+v", want, info.ClientRect)
+	}
+	if want := (Rect{Left: 319, Top: 149, Right: 2241, Bottom: 1261}); info.Frame != want {
+		t.Fatalf("Expected the caption and borders to lie outside, got %+v", info.Frame)
+	}
+}
+
+func TestSetGeometryWithoutBorders(t *testing.T) {
+	// e.g. a borderless game window or Windows without the compositor
+	fake := NewFake(Info{Handle: 1, Visible: true, Rect: Rect{Right: 800, Bottom: 600}})
+	useBackend(t, fake)
+
+	if err := SetGeometry(context.Background(), 1, windowSettings("")); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := fake.Info(1); info.Rect != (Rect{Left: 320, Top: 180, Right: 2240, Bottom: 1260}) {
+		t.Fatalf("Unexpected rect %+v", info.Rect)
+	}
+}
+
+func TestSetGeometryAlreadyCorrect(t *testing.T) {
+	fake := NewFake(bordered(1, Rect{Left: 320, Top: 180, Right: 2240, Bottom: 1260}))
+	moves := 0
+	fake.SetLimit(1, func(r Rect) Rect {
+		moves++
+		return r
+	})
+	useBackend(t, fake)
+
+	if err := SetGeometry(context.Background(), 1, windowSettings(config.SizeModeFrame)); err != nil {
+		t.Fatal(err)
+	}
+	if moves != 0 {
+		t.Fatalf("Expected the window not to be moved, got %d moves", moves)
+	}
+}
+
+func TestSetGeometryClamped(t *testing.T) {
+	fake := NewFake(bordered(1, Rect{Right: 800, Bottom: 600}))
+	moves := 0
+	fake.SetLimit(1, func(r Rect) Rect {
+		moves++
+		r.Right = min(r.Right, r.Left+1280)
+		r.Bottom = min(r.Bottom, r.Top+720)
+		return r
+	})
+	useBackend(t, fake)
+
+	err := SetGeometry(context.Background(), 1, windowSettings(config.SizeModeFrame))
+	var mismatch *GeometryError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected a GeometryError, got %v", err)
+	}
+	if !strings.Contains(mismatch.Reason, "maximum size") {
+		t.Fatalf("Unexpected reason %q", mismatch.Reason)
+	}
+	if mismatch.Got.Width() != 1266 || mismatch.Got.Height() != 713 {
+		t.Fatalf("Expected the clamped frame to be reported, got %+v", mismatch.Got)
+	}
+	if moves != geometryAttempts {
+		t.Fatalf("Expected %d attempts, got %d", geometryAttempts, moves)
+	}
+}
+
+func TestSetGeometryCancelled(t *testing.T) {
+	fake := NewFake(bordered(1, Rect{Right: 800, Bottom: 600}))
+	moves := 0
+	fake.SetLimit(1, func(r Rect) Rect {
+		moves++
+		r.Right = min(r.Right, r.Left+1280)
+		return r
+	})
+	useBackend(t, fake)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := SetGeometry(ctx, 1, windowSettings(config.SizeModeFrame)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the retry to be cancelled, got %v", err)
+	}
+	if moves != 1 {
+		t.Fatalf("Expected a single attempt, got %d", moves)
+	}
+}
+
+func TestVerifyGeometry(t *testing.T) {
+	wanted := Rect{Left: 320, Top: 180, Right: 2240, Bottom: 1260}
+	monitor := Monitor{Rect: Rect{Right: 2560, Bottom: 1440}}
+
+	tests := []struct {
+		name   string
+		info   Info
+		wanted Rect
+		reason string
+	}{
+		{"match", Info{Frame: wanted}, wanted, ""},
+		{"minimized", Info{Frame: Rect{Right: 160, Bottom: 28}, Minimized: true}, wanted, "minimized"},
+		{"maximized", Info{Frame: monitor.Rect, Maximized: true, Monitor: monitor}, wanted, "maximized"},
+		{"moved", Info{Frame: Rect{Right: 1920, Bottom: 1080}, Monitor: monitor}, wanted, "different position"},
+		{"monitor", Info{Frame: monitor.Rect, Monitor: monitor}, Rect{Right: 3840, Bottom: 2160}, "size of its monitor"},
+		{"clamped", Info{Frame: Rect{Left: 320, Top: 180, Right: 1600, Bottom: 900}, Monitor: monitor}, wanted, "maximum size"},
+		{"minimum", Info{Frame: Rect{Right: 1024, Bottom: 768}, Monitor: monitor}, Rect{Right: 640, Bottom: 480}, "minimum size"},
+	}
+
+	for _, test := range tests {
+		err := verifyGeometry(test.info, test.wanted, config.SizeModeFrame)
+		if test.reason == "" {
+			if err != nil {
+				t.Errorf("%s: expected no error, got %v", test.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.reason) {
+			t.Errorf("%s: expected reason %q, got %v", test.name, test.reason, err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package window
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// guardNewWindow is how long after being shown a window counts as new. Windows that take focus later were most likely
+// switched to by the user.
+var guardNewWindow = 3 * time.Second
+
+// guard keeps new windows of other apps from taking focus from the managed app that has it, see
+// config.GuardFocusSettings.
+var guard = &focusGuard{shown: make(map[Handle]time.Time)}
+
+type focusGuard struct {
+	mu sync.Mutex
+	// owner is the window of the managed app which is guarded, 0 if none is
+	owner      Handle
+	executable string
+	// shown are the times windows were shown at, only as long as they count as new
+	shown map[Handle]time.Time
+}
+
+// windowShown notes that the window was shown at the given time, called from the event hook before the window can
+// take focus.
+func (g *focusGuard) windowShown(h Handle, t time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for shown, at := range g.shown {
+		if t.Sub(at) > guardNewWindow {
+			delete(g.shown, shown)
+		}
+	}
+	g.shown[h] = t
+}
+
+// arm starts guarding the window of the managed app if the app guards its focus.
+func (g *focusGuard) arm(h Handle, executable string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if app, _ := config.GetManagedApp(executable); app.GuardFocus.Enabled {
+		g.owner, g.executable = h, executable
+	}
+}
+
+// intercept decides whether the window which just got focus took it from the guarded app. If so, the window is put
+// behind the app, which gets focus back. Otherwise the app lost focus for good and is no longer guarded.
+//
+// Returns whether the window was kept from taking focus.
+func (g *focusGuard) intercept(h Handle, pid uint32, executable string, now time.Time) bool {
+	g.mu.Lock()
+	owner, ownerExecutable := g.owner, g.executable
+	shown, isNew := g.shown[h]
+	if h != owner {
+		g.owner, g.executable = 0, ""
+	}
+	g.mu.Unlock()
+
+	if owner == 0 || h == owner || !isNew || now.Sub(shown) > guardNewWindow || pausing.paused() {
+		return false
+	}
+	app, _ := config.GetManagedApp(ownerExecutable)
+	if slices.ContainsFunc(app.GuardFocus.Allow, func(allow string) bool { return strings.EqualFold(allow, executable) }) {
+		return false
+	}
+	info, err := backend.Info(owner)
+	if err != nil || info.PID == pid || info.Minimized || !info.Visible {
+		return false
+	}
+
+	if err := backend.PlaceBehind(h, owner); err != nil {
+		recordActivity(ownerExecutable, "Could not keep %s from taking focus from %s: %v", executable, ownerExecutable, err)
+		return false
+	}
+	if err := backend.Activate(owner); err != nil {
+		recordActivity(ownerExecutable, "Put %s behind %s, but could not give focus back: %v", executable, ownerExecutable, err)
+		return true
+	}
+	recordActivity(ownerExecutable, "Kept %s from taking focus from %s", executable, ownerExecutable)
+
+	// the app keeps being guarded after getting focus back
+	g.mu.Lock()
+	g.owner, g.executable = owner, ownerExecutable
+	g.mu.Unlock()
+	return true
+}
@@ -0,0 +1,100 @@
+package window
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// useActivities empties the activity history for the duration of the test.
+func useActivities(t *testing.T) {
+	t.Helper()
+	activities.Lock()
+	previous := activities.list
+	activities.list = nil
+	activities.Unlock()
+	t.Cleanup(func() {
+		activities.Lock()
+		activities.list = previous
+		activities.Unlock()
+	})
+}
+
+// useGuard replaces the focus guard with an unarmed one for the duration of the test.
+func useGuard(t *testing.T) {
+	t.Helper()
+	previous := guard
+	guard = &focusGuard{shown: make(map[Handle]time.Time)}
+	t.Cleanup(func() { guard = previous })
+}
+
+func TestGuardFocus(t *testing.T) {
+	gameApp := game(config.StateSettings{})
+	gameApp.GuardFocus = config.GuardFocusSettings{Enabled: true, Allow: []string{"discord.exe"}}
+	useApps(t, gameApp)
+
+	fake := NewFake(
+		Info{Handle: 1, PID: 10, Title: "Game", Visible: true, Rect: Rect{Right: 1920, Bottom: 1080}, Monitor: left},
+		Info{Handle: 2, PID: 20, Title: "Updater", Visible: true, ExStyle: WS_EX_TOPMOST, Monitor: left},
+		Info{Handle: 3, PID: 30, Title: "Discord", Visible: true, Monitor: left},
+		Info{Handle: 4, PID: 40, Title: "Browser", Visible: true, Monitor: left},
+	)
+	fake.SetMonitors(left, right)
+	useBackend(t, fake)
+	useIndex(t, NewIndex((&executables{names: map[uint32]string{10: "Game.exe", 20: "Updater.exe", 30: "Discord.exe", 40: "Browser.exe"}}).resolve))
+	useMainWindows(t)
+	useJournal(t, openJournal(t, ""))
+	useActivities(t)
+	useGuard(t)
+
+	focus := func(h Handle) {
+		t.Helper()
+		fake.SetForeground(h)
+		if err := applyForeground(context.Background(), h); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	focus(1)
+	guard.windowShown(2, time.Now())
+	focus(2)
+	windows, _ := fake.Windows()
+	if fake.Foreground() != 1 || windows[0].Handle != 1 || windows[1].Handle != 2 || windows[1].ExStyle&WS_EX_TOPMOST != 0 {
+		t.Fatalf("Expected the updater to be put behind the game, which gets focus back, got %+v", windows)
+	}
+	if history := Activities(); len(history) != 1 || !strings.Contains(history[0].Message, "Updater.exe") {
+		t.Fatalf("Expected the updater to be in the activity history, got %+v", history)
+	}
+
+	// the allowed app may take focus, after which the game is no longer guarded
+	guard.windowShown(3, time.Now())
+	focus(3)
+	if fake.Foreground() != 3 {
+		t.Fatal("Expected the allowed app to keep focus")
+	}
+	focus(2)
+	if fake.Foreground() != 2 {
+		t.Fatal("Expected the updater to keep focus after the game lost it")
+	}
+
+	// switching to a window that has been there for a while is up to the user
+	focus(1)
+	focus(4)
+	if fake.Foreground() != 4 || len(Activities()) != 1 {
+		t.Fatalf("Expected the old window to keep focus, got %+v", Activities())
+	}
+}
+
+func TestActivityLimit(t *testing.T) {
+	useActivities(t)
+	for i := range activityLimit + 5 {
+		recordActivity("Game.exe", "activity %d", i)
+	}
+	history := Activities()
+	if len(history) != activityLimit || history[0].Message != "activity 5" {
+		t.Fatalf("Expected the oldest activities to be dropped, got %d starting with %q", len(history), history[0].Message)
+	}
+}
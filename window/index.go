@@ -0,0 +1,296 @@
+package window
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/skryvvara/focusframe/process"
+)
+
+// Index is an in-memory view of all top-level windows, the processes owning them and their executables.
+//
+// It is filled once at startup and then kept current by window events, so looking up the executable of a window, the
+// processes of an executable or the windows of a process doesn't require any system calls.
+type Index struct {
+	mu        sync.RWMutex
+	windows   map[Handle]uint32                // window -> PID
+	processes map[uint32]*indexedProcess       // PID -> process
+	byName    map[string]map[uint32]bool       // lower-cased executable -> PIDs
+	resolve   func(pid uint32) (string, error) // resolves the executable name of a PID
+	filled    bool                             // whether all windows were added, see Fill
+}
+
+type indexedProcess struct {
+	executable string
+	windows    map[Handle]bool
+}
+
+// NewIndex returns an empty Index which uses resolve to look up the executable of new processes.
+func NewIndex(resolve func(pid uint32) (string, error)) *Index {
+	return &Index{
+		windows:   make(map[Handle]uint32),
+		processes: make(map[uint32]*indexedProcess),
+		byName:    make(map[string]map[uint32]bool),
+		resolve:   resolve,
+	}
+}
+
+// index is the Index used by all package level functions.
+var index = NewIndex(executableOfPID)
+
+// Fill adds all given windows to the index. Until the index was filled WindowsOf doesn't know all windows of a
+// process. Windows of processes which can't be resolved are skipped, see Add.
+func (x *Index) Fill(windows []Info) {
+	for _, info := range windows {
+		_ = x.Add(info)
+	}
+
+	x.mu.Lock()
+	x.filled = true
+	x.mu.Unlock()
+}
+
+// Add adds the window to the index. The executable is only resolved for the first window of a process. If that fails,
+// e.g. for a protected process, the window is left out so the next event of the window resolves it again.
+//
+// Returns either nil or the error of resolving the executable.
+func (x *Index) Add(info Info) error {
+	if info.PID == 0 {
+		return nil
+	}
+
+	x.mu.RLock()
+	p, known := x.processes[info.PID]
+	indexed := known && p.windows[info.Handle]
+	x.mu.RUnlock()
+	if indexed {
+		return nil
+	}
+
+	executable := ""
+	if !known {
+		var err error
+		if executable, err = x.resolve(info.PID); err != nil {
+			return err
+		}
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	// the window might belong to a different process if the handle was reused
+	x.removeLocked(info.Handle)
+
+	p, known = x.processes[info.PID]
+	if !known {
+		p = &indexedProcess{executable: executable, windows: make(map[Handle]bool)}
+		x.processes[info.PID] = p
+		if executable != "" {
+			name := strings.ToLower(executable)
+			if x.byName[name] == nil {
+				x.byName[name] = make(map[uint32]bool)
+			}
+			x.byName[name][info.PID] = true
+		}
+	}
+	p.windows[info.Handle] = true
+	x.windows[info.Handle] = info.PID
+	return nil
+}
+
+// Remove removes the window from the index. Processes without any windows left are removed as well, since their PID
+// might be reused.
+func (x *Index) Remove(h Handle) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.removeLocked(h)
+}
+
+func (x *Index) removeLocked(h Handle) {
+	pid, ok := x.windows[h]
+	if !ok {
+		return
+	}
+	delete(x.windows, h)
+
+	p := x.processes[pid]
+	delete(p.windows, h)
+	if len(p.windows) > 0 {
+		return
+	}
+
+	delete(x.processes, pid)
+	name := strings.ToLower(p.executable)
+	delete(x.byName[name], pid)
+	if len(x.byName[name]) == 0 {
+		delete(x.byName, name)
+	}
+}
+
+// Owner returns the PID and executable of the process owning the window.
+func (x *Index) Owner(h Handle) (uint32, string, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	pid, ok := x.windows[h]
+	if !ok || x.processes[pid].executable == "" {
+		return 0, "", false
+	}
+	return pid, x.processes[pid].executable, true
+}
+
+// ProcessIDs returns the PIDs of all indexed processes with the given executable in ascending order.
+func (x *Index) ProcessIDs(executable string) []uint32 {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	var pids []uint32
+	for pid := range x.byName[strings.ToLower(executable)] {
+		pids = append(pids, pid)
+	}
+	slices.Sort(pids)
+	return pids
+}
+
+// WindowsOf returns the handles of all indexed windows of the process. The second return value is false if the index
+// was not filled yet, so the list might be incomplete.
+func (x *Index) WindowsOf(pid uint32) ([]Handle, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	p, ok := x.processes[pid]
+	if !ok {
+		return nil, x.filled
+	}
+	handles := make([]Handle, 0, len(p.windows))
+	for h := range p.windows {
+		handles = append(handles, h)
+	}
+	return handles, x.filled
+}
+
+// syncWindow brings the index, the main window tracker, the enforcer and the journal up to date after the window was
+// shown, renamed, moved or destroyed, and applies the settings of managed apps triggered by the change. Since events
+// of the same window are coalesced, the current state of the window is used instead of the kind of the event.
+func syncWindow(ev Event) {
+	info, err := backend.Info(ev.Handle)
+	if err != nil {
+		index.Remove(ev.Handle)
+		mainWindows.handle(Event{Kind: EventDestroyed, Handle: ev.Handle, Time: ev.Time})
+		enforcement.forget(ev.Handle)
+		if err := journal.Forget(ev.Handle); err != nil {
+			log.Println("Error updating journal:", err)
+		}
+		triggers.forget(ev.Handle)
+		return
+	}
+
+	if info.Parent != 0 {
+		return
+	}
+	// windows of processes that can't be resolved are retried on their next event
+	_ = index.Add(info)
+	mainWindows.handle(Event{Kind: EventShown, Handle: ev.Handle, Time: ev.Time})
+	enforcement.check(info, ev.Time)
+	applyOnChange(info)
+}
+
+// ownerOfWindow returns the PID and executable of the process owning the window, from the index if possible.
+func ownerOfWindow(h Handle) (uint32, string, error) {
+	if pid, executable, ok := index.Owner(h); ok {
+		return pid, executable, nil
+	}
+
+	info, err := backend.Info(h)
+	if err != nil {
+		return 0, "", err
+	}
+	if err := index.Add(info); err != nil {
+		return 0, "", fmt.Errorf("executable of process %d could not be resolved: %w", info.PID, err)
+	}
+	if pid, executable, ok := index.Owner(h); ok {
+		return pid, executable, nil
+	}
+	return 0, "", fmt.Errorf("executable of process %d could not be resolved", info.PID)
+}
+
+// mainWindowOf returns the main window of the process, see SelectMainWindow.
+// On success the handle of the window is returned otherwise the return value is 0.
+func mainWindowOf(pid uint32) Handle {
+	windows, err := processWindows(pid)
+	if err != nil {
+		log.Println("Error listing windows:", err)
+		return 0
+	}
+
+	if win, ok := SelectMainWindow(windows, pid, activations.snapshot()); ok {
+		return win.Handle
+	}
+	return 0
+}
+
+// processIDOfExecutable returns the PID of the running process with the given executable, from the index if possible.
+// Of several processes, e.g. a launcher and the game or helper processes, the one with the best main window is
+// returned, see SelectMainWindow. If none has a main window the lowest PID is returned.
+//
+// Returns either the PID or an error wrapping process.ErrProcessNotFound if no such process is running.
+func processIDOfExecutable(executable string) (uint32, error) {
+	pids := index.ProcessIDs(executable)
+	if len(pids) == 0 {
+		var err error
+		if pids, err = process.FindByName(executable); err != nil {
+			return 0, err
+		}
+		slices.Sort(pids)
+	}
+	if len(pids) == 0 {
+		return 0, &process.Error{Op: "find", Name: executable, Err: process.ErrProcessNotFound}
+	}
+	if len(pids) == 1 {
+		return pids[0], nil
+	}
+
+	lastActive := activations.snapshot()
+	best, bestPID := Info{}, pids[0]
+	found := false
+	for _, pid := range pids {
+		windows, err := processWindows(pid)
+		if err != nil {
+			return 0, err
+		}
+		win, ok := SelectMainWindow(windows, pid, lastActive)
+		if ok && (!found || betterMainWindow(win, best, lastActive)) {
+			best, bestPID, found = win, pid, true
+		}
+	}
+	return bestPID, nil
+}
+
+// processWindows returns the state of all visible windows of the process, using the index where possible instead of
+// enumerating all windows.
+func processWindows(pid uint32) ([]Info, error) {
+	handles, complete := index.WindowsOf(pid)
+	if !complete || len(handles) == 0 {
+		all, err := backend.Windows()
+		if err != nil {
+			return nil, err
+		}
+		var windows []Info
+		for _, info := range all {
+			if info.PID == pid {
+				windows = append(windows, info)
+			}
+		}
+		return windows, nil
+	}
+
+	windows := make([]Info, 0, len(handles))
+	for _, h := range handles {
+		info, err := backend.Info(h)
+		if err != nil || !info.Visible {
+			continue
+		}
+		windows = append(windows, info)
+	}
+	return windows, nil
+}
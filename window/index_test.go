@@ -0,0 +1,265 @@
+package window
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/skryvvara/focusframe/process"
+)
+
+// useIndex replaces the package index for the duration of the test.
+func useIndex(t testing.TB, x *Index) {
+	t.Helper()
+	previous := index
+	index = x
+	t.Cleanup(func() { index = previous })
+}
+
+// executables resolves PIDs from a fixed table and counts the lookups.
+type executables struct {
+	names   map[uint32]string
+	lookups int
+}
+
+func (e *executables) resolve(pid uint32) (string, error) {
+	e.lookups++
+	if name, ok := e.names[pid]; ok {
+		return name, nil
+	}
+	return "", fmt.Errorf("process %d does not exist", pid)
+}
+
+func sortedHandles(handles []Handle) []Handle {
+	sort.Slice(handles, func(i, j int) bool { return handles[i] < handles[j] })
+	return handles
+}
+
+func TestIndex(t *testing.T) {
+	exes := &executables{names: map[uint32]string{10: "Game.exe", 20: "game.exe", 30: "Other.exe"}}
+	x := NewIndex(exes.resolve)
+	x.Fill([]Info{
+		{Handle: 1, PID: 10},
+		{Handle: 2, PID: 10},
+		{Handle: 3, PID: 20},
+		{Handle: 4, PID: 30},
+	})
+
+	if exes.lookups != 3 {
+		t.Fatalf("Expected one lookup per process, got %d", exes.lookups)
+	}
+	if pid, exe, ok := x.Owner(2); !ok || pid != 10 || exe != "Game.exe" {
+		t.Fatalf("Unexpected owner of window 2: %d %q %v", pid, exe, ok)
+	}
+	if pids := x.ProcessIDs("GAME.EXE"); len(pids) != 2 {
+		t.Fatalf("Expected both game processes, got %v", pids)
+	}
+	handles, complete := x.WindowsOf(10)
+	if handles = sortedHandles(handles); !complete || len(handles) != 2 || handles[0] != 1 || handles[1] != 2 {
+		t.Fatalf("Unexpected windows of process 10: %v", handles)
+	}
+
+	x.Remove(1)
+	if _, _, ok := x.Owner(1); ok {
+		t.Fatal("Expected window 1 to be removed")
+	}
+	if pids := x.ProcessIDs("Game.exe"); len(pids) != 2 {
+		t.Fatalf("Expected process 10 to stay while it has windows, got %v", pids)
+	}
+
+	x.Remove(2)
+	if pids := x.ProcessIDs("Game.exe"); len(pids) != 1 || pids[0] != 20 {
+		t.Fatalf("Expected process 10 to be removed with its last window, got %v", pids)
+	}
+	if handles, _ := x.WindowsOf(10); len(handles) != 0 {
+		t.Fatalf("Expected no windows of process 10, got %v", handles)
+	}
+}
+
+func TestIndexHandleReused(t *testing.T) {
+	exes := &executables{names: map[uint32]string{10: "Game.exe", 20: "Other.exe"}}
+	x := NewIndex(exes.resolve)
+	x.Add(Info{Handle: 1, PID: 10})
+	x.Add(Info{Handle: 1, PID: 20})
+
+	if pid, exe, ok := x.Owner(1); !ok || pid != 20 || exe != "Other.exe" {
+		t.Fatalf("Expected window 1 to belong to process 20, got %d %q %v", pid, exe, ok)
+	}
+	if pids := x.ProcessIDs("Game.exe"); len(pids) != 0 {
+		t.Fatalf("Expected process 10 to be removed, got %v", pids)
+	}
+}
+
+func TestIndexUnresolvable(t *testing.T) {
+	exes := &executables{}
+	x := NewIndex(exes.resolve)
+	if err := x.Add(Info{Handle: 1, PID: 10}); err == nil {
+		t.Fatal("Expected an error for the protected process")
+	}
+	if _, _, ok := x.Owner(1); ok {
+		t.Fatal("Expected no owner for a process without executable")
+	}
+
+	// the failure is not cached, so the executable is resolved once it becomes available
+	exes.names = map[uint32]string{10: "Game.exe"}
+	if err := x.Add(Info{Handle: 1, PID: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if exes.lookups != 2 {
+		t.Fatalf("Expected the lookup to be retried, got %d lookups", exes.lookups)
+	}
+	if _, executable, ok := x.Owner(1); !ok || executable != "Game.exe" {
+		t.Fatalf("Expected Game.exe, got %q", executable)
+	}
+}
+
+func TestSyncWindow(t *testing.T) {
+	fake := NewFake(Info{Handle: 1, PID: 10, Title: "Game", Visible: true})
+	useBackend(t, fake)
+	useIndex(t, NewIndex((&executables{names: map[uint32]string{10: "Game.exe"}}).resolve))
+
+	fake.Add(Info{Handle: 2, PID: 10, Title: "Launcher", Visible: true})
+	fake.Add(Info{Handle: 3, PID: 10, Title: "Child", Visible: true, Parent: 2})
+	syncWindow(Event{Kind: EventShown, Handle: 2})
+	syncWindow(Event{Kind: EventShown, Handle: 3})
+
+	if handles, _ := index.WindowsOf(10); len(handles) != 1 || handles[0] != 2 {
+		t.Fatalf("Expected only the top-level window to be indexed, got %v", handles)
+	}
+
+	fake.Remove(2)
+	syncWindow(Event{Kind: EventNameChanged, Handle: 2})
+	if handles, _ := index.WindowsOf(10); len(handles) != 0 {
+		t.Fatalf("Expected the destroyed window to be removed, got %v", handles)
+	}
+}
+
+func TestOwnerOfWindowFallsBack(t *testing.T) {
+	useBackend(t, NewFake(Info{Handle: 1, PID: 10, Title: "Game", Visible: true}))
+	exes := &executables{names: map[uint32]string{10: "Game.exe"}}
+	useIndex(t, NewIndex(exes.resolve))
+
+	for i := 0; i < 2; i++ {
+		pid, exe, err := ownerOfWindow(1)
+		if err != nil || pid != 10 || exe != "Game.exe" {
+			t.Fatalf("Unexpected owner of window 1: %d %q %v", pid, exe, err)
+		}
+	}
+	if exes.lookups != 1 {
+		t.Fatalf("Expected the second lookup to use the index, got %d lookups", exes.lookups)
+	}
+	if _, _, err := ownerOfWindow(2); err == nil {
+		t.Fatal("Expected an error for a missing window")
+	}
+}
+
+func TestOwnerOfWindowWrapsResolveError(t *testing.T) {
+	useBackend(t, NewFake(Info{Handle: 1, PID: 10, Title: "Anti-Cheat", Visible: true}))
+	useIndex(t, NewIndex(func(pid uint32) (string, error) {
+		return "", &process.Error{Op: "open", PID: pid, Err: process.ErrAccessDenied}
+	}))
+
+	if _, _, err := ownerOfWindow(1); !errors.Is(err, process.ErrAccessDenied) {
+		t.Fatalf("Expected the resolve error to be wrapped, got %v", err)
+	}
+}
+
+func TestProcessIDOfExecutable(t *testing.T) {
+	fake := NewFake(
+		Info{Handle: 1, PID: 10, Title: "Launcher", Visible: true, Style: WS_CAPTION, Rect: Rect{Right: 400, Bottom: 300}},
+		Info{Handle: 2, PID: 20, Title: "Game", Visible: true, Style: WS_CAPTION, Rect: Rect{Right: 1280, Bottom: 720}},
+		Info{Handle: 3, PID: 40, Title: "", Visible: true},
+		Info{Handle: 4, PID: 30, Title: "", Visible: true},
+	)
+	useBackend(t, fake)
+	exes := &executables{names: map[uint32]string{10: "Game.exe", 20: "Game.exe", 30: "Game.exe", 40: "Game.exe"}}
+	useIndex(t, NewIndex(exes.resolve))
+	all, _ := fake.Windows()
+	index.Fill(all)
+
+	for i := 0; i < 5; i++ {
+		if pid, err := processIDOfExecutable("Game.exe"); err != nil || pid != 20 {
+			t.Fatalf("Expected the process with the biggest main window, got %d, %v", pid, err)
+		}
+	}
+
+	fake.Remove(1)
+	fake.Remove(2)
+	index.Remove(1)
+	index.Remove(2)
+	if pid, err := processIDOfExecutable("Game.exe"); err != nil || pid != 30 {
+		t.Fatalf("Expected the lowest PID of processes without a main window, got %d, %v", pid, err)
+	}
+}
+
+// desktop returns a Fake with a busy desktop of 100 processes with 3 windows each.
+func desktop() (*Fake, *executables) {
+	fake := NewFake()
+	exes := &executables{names: make(map[uint32]string)}
+	for pid := uint32(1); pid <= 100; pid++ {
+		exes.names[pid] = fmt.Sprintf("App%d.exe", pid)
+		for i := 0; i < 3; i++ {
+			fake.Add(Info{
+				Handle:  Handle(pid*10 + uint32(i)),
+				PID:     pid,
+				Title:   fmt.Sprintf("Window %d", i),
+				Visible: true,
+				Style:   WS_CAPTION,
+				Rect:    Rect{Right: 100 * (i + 1), Bottom: 100 * (i + 1)},
+			})
+		}
+	}
+	return fake, exes
+}
+
+// BenchmarkForegroundToApply measures the lookups between a foreground change and applying the settings, once
+// without the index (enumerating all windows and resolving the executable of every window like before) and once with a
+// filled index.
+func BenchmarkForegroundToApply(b *testing.B) {
+	fake, exes := desktop()
+	useBackend(b, fake)
+
+	b.Run("enumerate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			all, err := backend.Windows()
+			if err != nil {
+				b.Fatal(err)
+			}
+			var pid uint32
+			for _, info := range all {
+				if _, err := exes.resolve(info.PID); err != nil {
+					b.Fatal(err)
+				}
+				if info.Handle == 501 {
+					pid = info.PID
+				}
+			}
+			var windows []Info
+			for _, info := range all {
+				if info.PID == pid {
+					windows = append(windows, info)
+				}
+			}
+			if win, ok := SelectMainWindow(windows, pid, activations.snapshot()); !ok || win.Handle != 502 {
+				b.Fatalf("Expected window 502, got %d", win.Handle)
+			}
+		}
+	})
+
+	b.Run("indexed", func(b *testing.B) {
+		useIndex(b, NewIndex(exes.resolve))
+		all, _ := fake.Windows()
+		index.Fill(all)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			pid, _, err := ownerOfWindow(501)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if h := mainWindowOf(pid); h != 502 {
+				b.Fatalf("Expected window 502, got %d", h)
+			}
+		}
+	})
+}
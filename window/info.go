@@ -0,0 +1,86 @@
+package window
+
+import "strings"
+
+// Handle identifies a top-level window, on Windows this is the HWND.
+type Handle uintptr
+
+// Rect is a rectangle in screen coordinates. Right and Bottom are exclusive.
+type Rect struct {
+	Left, Top, Right, Bottom int
+}
+
+// Width returns the width of the rect.
+func (r Rect) Width() int {
+	return r.Right - r.Left
+}
+
+// Height returns the height of the rect.
+func (r Rect) Height() int {
+	return r.Bottom - r.Top
+}
+
+// Area returns the area of the rect or 0 if the rect is empty.
+func (r Rect) Area() int {
+	if r.Empty() {
+		return 0
+	}
+	return r.Width() * r.Height()
+}
+
+// Empty reports whether the rect has no area.
+func (r Rect) Empty() bool {
+	return r.Width() <= 0 || r.Height() <= 0
+}
+
+// Contains reports whether the other rect lies completely within r.
+func (r Rect) Contains(other Rect) bool {
+	return other.Left >= r.Left && other.Top >= r.Top && other.Right <= r.Right && other.Bottom <= r.Bottom
+}
+
+// Monitor describes the display a window is on.
+type Monitor struct {
+	Handle   uintptr
+	Rect     Rect // Full bounds of the monitor
+	WorkArea Rect // Bounds of the monitor without the taskbar and docked toolbars
+	Primary  bool
+	// ID identifies the display hardware like "DELA026#5&10a58c63&0&UID16777488", empty if unknown. Unlike Handle it
+	// stays the same across restarts.
+	ID string
+}
+
+// monitorID returns the ID of a monitor from the device interface name of its display, e.g.
+// `\\?\DISPLAY#DELA026#5&10a58c63&0&UID16777488#{e6f07b5f-ee97-4a90-b076-33f57bf4eaa7}`, or an empty string if
+// the name has a different form.
+func monitorID(deviceInterface string) string {
+	parts := strings.Split(deviceInterface, "#")
+	if len(parts) != 4 {
+		return ""
+	}
+	return parts[1] + "#" + parts[2]
+}
+
+// Info is a snapshot of the state of a top-level window.
+type Info struct {
+	Handle Handle
+	Title  string
+	Class  string
+	Owner  Handle // Window owning this window (e.g. the main window of a dialog), 0 if unowned
+	Parent Handle // Parent window, 0 for windows that are children of the desktop
+	PID    uint32
+
+	Style   uint32
+	ExStyle uint32
+
+	Visible    bool
+	Cloaked    bool // The window is hidden by the compositor, e.g. because it is on another virtual desktop
+	Minimized  bool
+	Maximized  bool
+	Fullscreen bool // The window covers its whole monitor and has no caption
+
+	Rect       Rect // Window rect including the frame and invisible resize borders
+	NormalRect Rect // Window rect the window has when it is neither minimized nor maximized
+	Frame      Rect // Visible bounds of the window, without invisible resize borders
+	ClientRect Rect // Client area in screen coordinates
+	Monitor    Monitor
+}
@@ -0,0 +1,252 @@
+package window
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+)
+
+// OriginalState is the state of a window before FocusFrame changed it for the first time.
+type OriginalState struct {
+	Handle     Handle `json:"handle"`
+	PID        uint32 `json:"pid"`
+	Executable string `json:"executable"`
+	Style      uint32 `json:"style"`
+	ExStyle    uint32 `json:"ex_style"`
+	Rect       Rect   `json:"rect"`
+	Maximized  bool   `json:"maximized"`
+}
+
+// Journal records the original state of every window FocusFrame changed, so the windows can be restored when their
+// app is no longer managed or FocusFrame quits.
+//
+// The journal is written to disk on every change, which allows restoring the windows after FocusFrame crashed or was
+// killed. A Journal without a path only lives in memory.
+type Journal struct {
+	mu      sync.Mutex
+	path    string
+	windows map[Handle]OriginalState
+}
+
+// journalFile is the on-disk format of the Journal.
+type journalFile struct {
+	Windows []OriginalState `json:"windows"`
+}
+
+// journal is the Journal used by all package level functions, see LoadJournal.
+var journal = &Journal{windows: make(map[Handle]OriginalState)}
+
+// OpenJournal reads the journal at the given path. A missing file results in an empty journal.
+//
+// Returns either the Journal or an error if the file exists but could not be read.
+func OpenJournal(path string) (*Journal, error) {
+	j := &Journal{path: path, windows: make(map[Handle]OriginalState)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return j, nil
+	}
+	if err != nil {
+		return j, err
+	}
+
+	var file journalFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return j, fmt.Errorf("invalid journal %s: %v", path, err)
+	}
+	for _, state := range file.Windows {
+		j.windows[state.Handle] = state
+	}
+	return j, nil
+}
+
+// Record remembers the state of the window unless it was already recorded, so the first state is kept when a window
+// is changed multiple times.
+func (j *Journal) Record(info Info, executable string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	// a handle that belongs to another process now was reused after the recorded window was destroyed
+	if state, ok := j.windows[info.Handle]; ok && state.PID == info.PID {
+		return nil
+	}
+
+	j.windows[info.Handle] = originalState(info, executable)
+	return j.save()
+}
+
+// originalState returns the state of the window to restore it to later. The rect of a minimized or maximized window
+// is the one it has when restored, so a maximized window is maximized again on the monitor it was on.
+func originalState(info Info, executable string) OriginalState {
+	r := info.Rect
+	if (info.Minimized || info.Maximized) && !info.NormalRect.Empty() {
+		r = info.NormalRect
+	}
+	return OriginalState{
+		Handle:     info.Handle,
+		PID:        info.PID,
+		Executable: executable,
+		Style:      info.Style,
+		ExStyle:    info.ExStyle,
+		Rect:       r,
+		Maximized:  info.Maximized,
+	}
+}
+
+// Forget removes the window from the journal.
+func (j *Journal) Forget(h Handle) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, ok := j.windows[h]; !ok {
+		return nil
+	}
+	delete(j.windows, h)
+	return j.save()
+}
+
+// Windows returns the original state of all recorded windows.
+func (j *Journal) Windows() []OriginalState {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	windows := make([]OriginalState, 0, len(j.windows))
+	for _, state := range j.windows {
+		windows = append(windows, state)
+	}
+	return windows
+}
+
+// save writes the journal to disk, the caller must hold the lock. The file is replaced atomically so a crash while
+// saving never leaves a broken journal behind. An empty journal removes the file.
+func (j *Journal) save() error {
+	if j.path == "" {
+		return nil
+	}
+	if len(j.windows) == 0 {
+		if err := os.Remove(j.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	file := journalFile{Windows: make([]OriginalState, 0, len(j.windows))}
+	for _, state := range j.windows {
+		file.Windows = append(file.Windows, state)
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, j.path)
+}
+
+// LoadJournal makes FocusFrame record the original state of windows in the journal at the given path.
+//
+// Returns the windows recorded by a previous run that didn't exit cleanly and which are still open, see
+// RestoreWindows. Recorded windows that no longer exist are dropped from the journal.
+func LoadJournal(path string) ([]OriginalState, error) {
+	j, err := OpenJournal(path)
+	if err != nil {
+		return nil, err
+	}
+	journal = j
+
+	var open []OriginalState
+	for _, state := range j.Windows() {
+		if info, err := backend.Info(state.Handle); err == nil && info.PID == state.PID {
+			open = append(open, state)
+			continue
+		}
+		if err := j.Forget(state.Handle); err != nil {
+			log.Println("Error updating journal:", err)
+		}
+	}
+	return open, nil
+}
+
+// recordOriginal adds the window to the journal before FocusFrame changes it for the first time.
+func recordOriginal(h Handle, executable string) error {
+	info, err := backend.Info(h)
+	if err != nil {
+		return err
+	}
+	return journal.Record(info, executable)
+}
+
+// restore puts the window back into its original state and removes it from the journal.
+func restore(state OriginalState) error {
+	defer func() {
+		if err := journal.Forget(state.Handle); err != nil {
+			log.Println("Error updating journal:", err)
+		}
+	}()
+	return restoreState(state)
+}
+
+// restoreState puts the window back into the given state, e.g. its original one.
+func restoreState(state OriginalState) error {
+	info, err := backend.Info(state.Handle)
+	if err != nil || info.PID != state.PID {
+		return nil // the window is gone, nothing to restore
+	}
+
+	// the window must not be changed back while it is being restored
+	enforcement.forget(state.Handle)
+
+	if info.Minimized || info.Maximized {
+		if err := backend.SetShowState(state.Handle, ShowNormal); err != nil {
+			return err
+		}
+	}
+
+	// replace all style bits with the original ones
+	original := StyleChange{
+		Decorations:  true,
+		Remove:       ^uint32(0),
+		Add:          state.Style,
+		RemoveEx:     ^uint32(0),
+		AddEx:        state.ExStyle,
+		FrameChanged: true,
+	}
+	if err := backend.SetStyle(state.Handle, original); err != nil {
+		return err
+	}
+	if err := backend.SetRect(state.Handle, state.Rect); err != nil {
+		return err
+	}
+	if state.Maximized {
+		return backend.SetShowState(state.Handle, ShowMaximized)
+	}
+	return nil
+}
+
+// RestoreWindows puts the given windows back into their original state.
+func RestoreWindows(windows []OriginalState) {
+	for _, state := range windows {
+		if err := restore(state); err != nil {
+			log.Printf("Error restoring window %#x of %s: %v\n", state.Handle, state.Executable, err)
+			continue
+		}
+		log.Printf("Restored window %#x of %s\n", state.Handle, state.Executable)
+	}
+}
+
+// RestoreAll puts all windows FocusFrame changed back into their original state.
+func RestoreAll() {
+	hideCompanions(0)
+	leaveFocusMode(0)
+	RestoreWindows(journal.Windows())
+}
+
+// Unmanage stops FocusFrame from managing the windows of the executable and restores their original state.
+func Unmanage(executable string) {
+	mainWindows.untrack(executable)
+	RestoreWindows(journaledWindows(executable))
+}
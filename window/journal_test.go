@@ -0,0 +1,150 @@
+package window
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useJournal replaces the package journal for the duration of the test.
+func useJournal(t *testing.T, j *Journal) {
+	t.Helper()
+	previous := journal
+	journal = j
+	t.Cleanup(func() { journal = previous })
+}
+
+func openJournal(t *testing.T, path string) *Journal {
+	t.Helper()
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return j
+}
+
+var original = Info{
+	Handle:  1,
+	PID:     10,
+	Visible: true,
+	Style:   WS_OVERLAPPEDWINDOW | WS_VISIBLE,
+	ExStyle: WS_EX_WINDOWEDGE,
+	Rect:    Rect{Left: 100, Top: 100, Right: 900, Bottom: 700},
+}
+
+func TestJournalKeepsFirstState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	j := openJournal(t, path)
+
+	if err := j.Record(original, "Game.exe"); err != nil {
+		t.Fatal(err)
+	}
+	changed := original
+	changed.Style = WS_POPUP | WS_VISIBLE
+	if err := j.Record(changed, "Game.exe"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the journal survives a restart
+	windows := openJournal(t, path).Windows()
+	if len(windows) != 1 || windows[0].Style != original.Style || windows[0].Rect != original.Rect {
+		t.Fatalf("Expected the original state to be kept, got %+v", windows)
+	}
+
+	// the handle was reused by another process
+	reused := changed
+	reused.PID = 20
+	if err := j.Record(reused, "Other.exe"); err != nil {
+		t.Fatal(err)
+	}
+	if windows := j.Windows(); len(windows) != 1 || windows[0].PID != 20 || windows[0].Executable != "Other.exe" {
+		t.Fatalf("Expected the reused handle to be recorded again, got %+v", windows)
+	}
+
+	if err := j.Forget(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Expected the empty journal to be removed, got %v", err)
+	}
+}
+
+func TestJournalInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenJournal(path); err == nil {
+		t.Fatal("Expected an error for an invalid journal")
+	}
+}
+
+func TestLoadJournal(t *testing.T) {
+	useJournal(t, journal)
+	useBackend(t, NewFake(original, Info{Handle: 3, PID: 99, Visible: true}))
+
+	path := filepath.Join(t.TempDir(), "journal.json")
+	crashed := openJournal(t, path)
+	for _, info := range []Info{original, {Handle: 2, PID: 10}, {Handle: 3, PID: 30}} {
+		if err := crashed.Record(info, "Game.exe"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// only window 1 is still open, window 2 is gone and the handle of window 3 was reused
+	unrestored, err := LoadJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unrestored) != 1 || unrestored[0].Handle != 1 {
+		t.Fatalf("Expected only window 1 to be restorable, got %+v", unrestored)
+	}
+	if windows := openJournal(t, path).Windows(); len(windows) != 1 {
+		t.Fatalf("Expected closed windows to be dropped from the journal, got %+v", windows)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	maximized := Info{Handle: 2, PID: 20, Visible: true, Style: WS_OVERLAPPEDWINDOW, Maximized: true,
+		Rect: Rect{Right: 2560, Bottom: 1440}, NormalRect: Rect{Left: 200, Top: 100, Right: 1480, Bottom: 820}}
+	fake := NewFake(original, maximized)
+	useBackend(t, fake)
+	useJournal(t, openJournal(t, ""))
+
+	for _, app := range []struct {
+		h          Handle
+		executable string
+	}{{1, "Game.exe"}, {2, "Other.exe"}} {
+		if err := recordOriginal(app.h, app.executable); err != nil {
+			t.Fatal(err)
+		}
+		if err := fake.SetShowState(app.h, ShowNormal); err != nil {
+			t.Fatal(err)
+		}
+		if err := fake.SetStyle(app.h, StyleChange{RemoveEx: WS_EX_WINDOWEDGE}); err != nil {
+			t.Fatal(err)
+		}
+		if err := fake.SetRect(app.h, Rect{Right: 1920, Bottom: 1080}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	Unmanage("Game.exe")
+	info, _ := fake.Info(1)
+	if info.Style != original.Style || info.ExStyle != original.ExStyle || info.Rect != original.Rect {
+		t.Fatalf("Expected window 1 to be restored to %+v, got %+v", original, info)
+	}
+	if info, _ := fake.Info(2); info.Style == maximized.Style {
+		t.Fatal("Expected only the windows of the unmanaged app to be restored")
+	}
+
+	RestoreAll()
+	info, _ = fake.Info(2)
+	// the window is moved back to where it goes when it is no longer maximized, then maximized again
+	if info.Style != maximized.Style || info.Rect != maximized.NormalRect || !info.Maximized {
+		t.Fatalf("Expected window 2 to be maximized again from %+v, got %+v", maximized.NormalRect, info)
+	}
+	if windows := journal.Windows(); len(windows) != 0 {
+		t.Fatalf("Expected the journal to be empty, got %+v", windows)
+	}
+}
@@ -0,0 +1,93 @@
+package window
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/skryvvara/focusframe/config"
+	"github.com/skryvvara/focusframe/process"
+)
+
+// OnLayoutChanged is called with a managed app that has layouts and its active layout whenever a layout is selected
+// or another one of these apps gets focus, e.g. to show the layout in the tray. It must not block.
+var OnLayoutChanged func(executable string, layout string)
+
+// layoutShown is the app whose layout was reported to OnLayoutChanged last, so focusing it again isn't reported.
+var layoutShown = struct {
+	sync.Mutex
+	executable string
+}{}
+
+// showLayout reports the active layout of the managed app to OnLayoutChanged. Apps without layouts are not reported,
+// and neither is the app reported last unless force is set.
+func showLayout(executable string, force bool) {
+	if len(config.LayoutNames(executable)) < 2 {
+		return
+	}
+	layoutShown.Lock()
+	if layoutShown.executable == executable && !force {
+		layoutShown.Unlock()
+		return
+	}
+	layoutShown.executable = executable
+	layoutShown.Unlock()
+
+	if OnLayoutChanged != nil {
+		OnLayoutChanged(executable, config.GetActiveLayout(executable))
+	}
+}
+
+// layoutApp returns the managed app a layout command refers to, the app owning the focused window if executable is
+// empty.
+func layoutApp(executable string) (string, error) {
+	if executable == "" {
+		_, focused, err := focusedApp()
+		if err != nil {
+			return "", err
+		}
+		executable = focused
+	}
+	if !isManaged(executable) {
+		return "", fmt.Errorf("%s: %w", executable, errNotManaged)
+	}
+	return executable, nil
+}
+
+// SelectLayout makes the layout with the given name the active one of the managed app and applies it. An empty
+// executable refers to the app owning the focused window. The layout is kept if the app is not running or window
+// management is paused, it is applied on the next trigger then.
+//
+// Returns an error if the app is not managed, has no such layout, the config could not be saved or the layout could
+// not be applied.
+func SelectLayout(executable string, layout string) error {
+	executable, err := layoutApp(executable)
+	if err != nil {
+		return err
+	}
+	if err := config.SetActiveLayout(executable, layout); err != nil {
+		return err
+	}
+	log.Printf("Selected layout %s of %s\n", layout, executable)
+	showLayout(executable, true)
+
+	err = Apply(executable)
+	if errors.Is(err, ErrPaused) || errors.Is(err, process.ErrProcessNotFound) {
+		log.Printf("Not applying layout %s of %s now: %v\n", layout, executable, err)
+		return nil
+	}
+	return err
+}
+
+// CycleLayout selects the layout following the active one of the managed app, see SelectLayout.
+//
+// Returns either the name of the selected layout or an error if SelectLayout failed.
+func CycleLayout(executable string) (string, error) {
+	executable, err := layoutApp(executable)
+	if err != nil {
+		return "", err
+	}
+	layout := config.NextLayout(executable)
+	return layout, SelectLayout(executable, layout)
+}
@@ -0,0 +1,58 @@
+package window
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// recordLayouts forgets the app whose layout was shown last and records every report for the duration of the test.
+func recordLayouts(t *testing.T) *[]string {
+	t.Helper()
+	var shown []string
+	layoutShown.Lock()
+	layoutShown.executable = ""
+	layoutShown.Unlock()
+	OnLayoutChanged = func(executable string, layout string) { shown = append(shown, executable+": "+layout) }
+	t.Cleanup(func() { OnLayoutChanged = nil })
+	return &shown
+}
+
+func TestShowLayout(t *testing.T) {
+	app := game(config.StateSettings{})
+	app.Layouts = []config.Layout{{Name: "wide", Dimensions: config.WindowSettings{Width: 2560, Height: 1080}}}
+	app.ActiveLayout = "wide"
+	plain := game(config.StateSettings{})
+	plain.Executable = "Plain.exe"
+	useApps(t, app, plain)
+	shown := recordLayouts(t)
+
+	showLayout("Game.exe", false)
+	showLayout("Game.exe", false)
+	showLayout("Plain.exe", false)
+	if len(*shown) != 1 || (*shown)[0] != "Game.exe: wide" {
+		t.Fatalf("Expected the layout of Game.exe to be shown once, got %v", *shown)
+	}
+
+	showLayout("Game.exe", true)
+	if len(*shown) != 2 {
+		t.Fatalf("Expected a forced report, got %v", *shown)
+	}
+}
+
+func TestSelectLayoutErrors(t *testing.T) {
+	useApps(t, game(config.StateSettings{}))
+	fake := NewFake()
+	useBackend(t, fake)
+
+	if err := SelectLayout("Other.exe", "wide"); !errors.Is(err, errNotManaged) {
+		t.Fatalf("Expected errNotManaged, got %v", err)
+	}
+	if _, err := CycleLayout(""); !errors.Is(err, ErrWindowNotFound) {
+		t.Fatalf("Expected ErrWindowNotFound without focus, got %v", err)
+	}
+	if err := SelectLayout("Game.exe", "missing"); err == nil {
+		t.Fatal("Expected an error selecting a missing layout")
+	}
+}
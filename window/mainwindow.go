@@ -0,0 +1,173 @@
+package window
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// mainWindowGrace is how long a managed process may be without a main window (e.g. between a splash screen being
+// destroyed and the game window being shown) before it is no longer tracked.
+const mainWindowGrace = 30 * time.Second
+
+// mainWindows tracks the main windows of managed processes and re-applies the settings when they are replaced, if the
+// app is applied on the replacement.
+var mainWindows = newMainWindowTracker(func(h Handle, pid uint32, executable string) {
+	if pausing.paused() || !triggers.allow(pid, executable, triggerReplaced) {
+		return
+	}
+	scheduler.Schedule(Job{
+		Handle: h,
+		Name:   triggerReplaced,
+		Run: func(ctx context.Context) error {
+			return applyToWindow(ctx, h, executable)
+		},
+	})
+})
+
+// activationLog remembers when each window was last activated.
+type activationLog struct {
+	mu    sync.Mutex
+	times map[Handle]time.Time
+}
+
+var activations = &activationLog{times: make(map[Handle]time.Time)}
+
+func (a *activationLog) record(h Handle, t time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.times[h] = t
+}
+
+func (a *activationLog) forget(h Handle) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.times, h)
+}
+
+// snapshot returns a copy of all activation times.
+func (a *activationLog) snapshot() map[Handle]time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	times := make(map[Handle]time.Time, len(a.times))
+	for h, t := range a.times {
+		times[h] = t
+	}
+	return times
+}
+
+// mainWindowTracker remembers the main window FocusFrame applied settings to for every managed process. When the
+// process replaces its main window, e.g. a splash screen is followed by the game window, the settings are applied to
+// the new main window.
+type mainWindowTracker struct {
+	mu        sync.Mutex
+	processes map[uint32]*trackedProcess
+	apply     func(h Handle, pid uint32, executable string)
+}
+
+type trackedProcess struct {
+	executable string
+	main       Handle
+	lostAt     time.Time // when the main window was destroyed without a replacement
+}
+
+func newMainWindowTracker(apply func(h Handle, pid uint32, executable string)) *mainWindowTracker {
+	return &mainWindowTracker{
+		processes: make(map[uint32]*trackedProcess),
+		apply:     apply,
+	}
+}
+
+// track registers h as the main window of the process with the given PID.
+func (t *mainWindowTracker) track(pid uint32, executable string, h Handle) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.processes[pid] = &trackedProcess{executable: executable, main: h}
+}
+
+// untrack stops tracking the process with the given executable, e.g. because it is no longer managed.
+func (t *mainWindowTracker) untrack(executable string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for pid, tp := range t.processes {
+		if tp.executable == executable {
+			delete(t.processes, pid)
+		}
+	}
+}
+
+// exited stops tracking the process with the given PID, which is no longer running.
+func (t *mainWindowTracker) exited(pid uint32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.processes, pid)
+}
+
+// handle updates the tracked main windows for the given event and re-applies the settings if a tracked process got
+// a new main window.
+func (t *mainWindowTracker) handle(ev Event) {
+	switch ev.Kind {
+	case EventForeground:
+		activations.record(ev.Handle, ev.Time)
+	case EventShown:
+		info, err := backend.Info(ev.Handle)
+		if err != nil {
+			return
+		}
+		t.reselect(info.PID, ev.Time)
+	case EventDestroyed:
+		activations.forget(ev.Handle)
+		t.mu.Lock()
+		var pids []uint32
+		for pid, tp := range t.processes {
+			if tp.main == ev.Handle {
+				tp.main = 0
+				tp.lostAt = ev.Time
+				pids = append(pids, pid)
+			}
+		}
+		t.mu.Unlock()
+		for _, pid := range pids {
+			t.reselect(pid, ev.Time)
+		}
+	}
+}
+
+// reselect selects the main window of a tracked process again and applies the settings if it changed.
+func (t *mainWindowTracker) reselect(pid uint32, now time.Time) {
+	t.mu.Lock()
+	tp, ok := t.processes[pid]
+	if !ok {
+		t.mu.Unlock()
+		return
+	}
+	if tp.main == 0 && !tp.lostAt.IsZero() && now.Sub(tp.lostAt) > mainWindowGrace {
+		delete(t.processes, pid)
+		t.mu.Unlock()
+		return
+	}
+	t.mu.Unlock()
+
+	windows, err := processWindows(pid)
+	if err != nil {
+		log.Println("Error listing windows:", err)
+		return
+	}
+
+	best, found := SelectMainWindow(windows, pid, activations.snapshot())
+
+	t.mu.Lock()
+	// the process might have exited, or its PID been reused, while the windows were listed
+	if current, ok := t.processes[pid]; !ok || current != tp || !found || best.Handle == tp.main {
+		t.mu.Unlock()
+		return
+	}
+	log.Printf("Main window of %s changed from %#x to %#x (%s)\n", tp.executable, tp.main, best.Handle, best.Title)
+	tp.main = best.Handle
+	tp.lostAt = time.Time{}
+	executable := tp.executable
+	t.mu.Unlock()
+
+	t.apply(best.Handle, pid, executable)
+}
@@ -0,0 +1,187 @@
+package window
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrPaused is returned by commands that apply settings while window management is paused.
+var ErrPaused = errors.New("window management is paused")
+
+// PauseStatus describes whether window management is paused and what resumes it.
+type PauseStatus struct {
+	Paused bool
+	// Until is when the pause ends, zero if it doesn't end by itself.
+	Until time.Time
+	// UntilBlurOf is the executable whose losing focus ends the pause, empty if it doesn't.
+	UntilBlurOf string
+}
+
+// String describes the status for the tray, e.g. "Paused until 15:04".
+func (s PauseStatus) String() string {
+	switch {
+	case !s.Paused:
+		return "Active"
+	case !s.Until.IsZero():
+		return fmt.Sprintf("Paused until %s", s.Until.Format("15:04"))
+	case s.UntilBlurOf != "":
+		return fmt.Sprintf("Paused until %s loses focus", s.UntilBlurOf)
+	default:
+		return "Paused"
+	}
+}
+
+// OnPauseChanged is called with the new status whenever window management is paused or resumed, e.g. to update the
+// tray. It must not block.
+var OnPauseChanged func(PauseStatus)
+
+// pausing is the pause state of window management. While paused, events are still tracked, e.g. the main windows of
+// apps and their titles, but no settings are applied.
+var pausing = &pauseState{}
+
+type pauseState struct {
+	mu     sync.Mutex
+	status PauseStatus
+	timer  *time.Timer
+	pauses int // number of pauses so far, identifies the current one
+	// focused is set once the app of UntilBlurOf had focus, the pause ends when it loses it afterwards
+	focused bool
+	// lastManaged is the managed app that had focus last, the app PauseUntilBlur refers to by default
+	lastManaged string
+}
+
+// paused reports whether window management is paused.
+func (p *pauseState) paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.status.Paused
+}
+
+// pause pauses window management with the given status, replacing a previous pause. A pause with a duration resumes
+// by itself after it.
+func (p *pauseState) pause(status PauseStatus, d time.Duration, focused bool) {
+	p.mu.Lock()
+	p.stopTimer()
+	p.pauses++
+	status.Paused = true
+	if d > 0 {
+		status.Until = time.Now().Add(d)
+		pause := p.pauses
+		p.timer = time.AfterFunc(d, func() { p.resume(pause) })
+	}
+	p.status = status
+	p.focused = focused
+	p.mu.Unlock()
+
+	log.Printf("Pausing window management, %s\n", strings.ToLower(status.String()))
+	p.changed(status)
+}
+
+// resume ends the pause, if any. A pause number other than 0 only ends that pause, so a timer doesn't end a pause
+// that replaced the one it was started for.
+func (p *pauseState) resume(pause int) {
+	p.mu.Lock()
+	if !p.status.Paused || pause != 0 && p.pauses != pause {
+		p.mu.Unlock()
+		return
+	}
+	p.stopTimer()
+	p.status = PauseStatus{}
+	p.focused = false
+	p.mu.Unlock()
+
+	log.Println("Resumed window management")
+	p.changed(PauseStatus{})
+}
+
+func (p *pauseState) stopTimer() {
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+}
+
+func (p *pauseState) changed(status PauseStatus) {
+	if OnPauseChanged != nil {
+		OnPauseChanged(status)
+	}
+}
+
+// focusChanged records that a window of the executable got focus. It ends a pause until the app lost focus once the
+// app had focus and another app gets it.
+func (p *pauseState) focusChanged(executable string, managed bool) {
+	p.mu.Lock()
+	if managed {
+		p.lastManaged = executable
+	}
+	blurOf := p.status.UntilBlurOf
+	if blurOf == "" {
+		p.mu.Unlock()
+		return
+	}
+	if executable == blurOf {
+		p.focused = true
+		p.mu.Unlock()
+		return
+	}
+	focused := p.focused
+	p.mu.Unlock()
+
+	if focused {
+		log.Printf("%s lost focus, resuming window management\n", blurOf)
+		p.resume(0)
+	}
+}
+
+// Pause stops applying settings to windows, a duration of zero pauses until Resume is called.
+func Pause(d time.Duration) {
+	pausing.pause(PauseStatus{}, d, false)
+}
+
+// PauseUntilBlur stops applying settings to windows until the app of the executable loses focus. An empty executable
+// refers to the managed app that had focus last, so the pause can be started from the tray, which takes the focus.
+// If the app doesn't have focus, the pause lasts until it got and lost it again.
+//
+// Returns an error if no executable is given and no managed app had focus yet.
+func PauseUntilBlur(executable string) error {
+	pausing.mu.Lock()
+	if executable == "" {
+		executable = pausing.lastManaged
+	}
+	pausing.mu.Unlock()
+	if executable == "" {
+		return errors.New("no managed app had focus yet")
+	}
+
+	focused := false
+	if _, foreground, err := focusedApp(); err == nil {
+		focused = foreground == executable
+	}
+	pausing.pause(PauseStatus{UntilBlurOf: executable}, 0, focused)
+	return nil
+}
+
+// Resume continues applying settings to windows after Pause. Windows are applied again on their next trigger.
+func Resume() {
+	pausing.resume(0)
+}
+
+// TogglePause pauses window management until it is resumed, or resumes it if it is paused.
+func TogglePause() {
+	if pausing.paused() {
+		Resume()
+	} else {
+		Pause(0)
+	}
+}
+
+// CurrentPauseStatus returns whether window management is paused and what resumes it.
+func CurrentPauseStatus() PauseStatus {
+	pausing.mu.Lock()
+	defer pausing.mu.Unlock()
+	return pausing.status
+}
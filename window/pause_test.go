@@ -0,0 +1,119 @@
+package window
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// usePausing replaces the pause state with an active one for the duration of the test and returns the channel every
+// change is sent on.
+func usePausing(t *testing.T) <-chan PauseStatus {
+	t.Helper()
+	changes := make(chan PauseStatus, 16)
+	previous := pausing
+	pausing = &pauseState{}
+	OnPauseChanged = func(status PauseStatus) { changes <- status }
+	t.Cleanup(func() {
+		Resume()
+		OnPauseChanged = nil
+		pausing = previous
+	})
+	return changes
+}
+
+func TestPauseTimed(t *testing.T) {
+	changes := usePausing(t)
+
+	Pause(20 * time.Millisecond)
+	if status := <-changes; !status.Paused || status.Until.IsZero() {
+		t.Fatalf("Expected a timed pause, got %+v", status)
+	}
+	select {
+	case status := <-changes:
+		if status.Paused || CurrentPauseStatus().Paused {
+			t.Fatalf("Expected the pause to end, got %+v", status)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the pause to end by itself")
+	}
+}
+
+func TestPauseReplacesTimedPause(t *testing.T) {
+	changes := usePausing(t)
+
+	Pause(10 * time.Millisecond)
+	Pause(0)
+	time.Sleep(50 * time.Millisecond)
+	if !CurrentPauseStatus().Paused || len(changes) != 2 {
+		t.Fatalf("Expected the timer of the replaced pause not to resume, got %d changes", len(changes))
+	}
+
+	TogglePause()
+	if CurrentPauseStatus().Paused {
+		t.Fatal("Expected toggling to resume")
+	}
+}
+
+func TestPauseUntilBlur(t *testing.T) {
+	usePausing(t)
+	useBackend(t, NewFake())
+
+	if err := PauseUntilBlur(""); err == nil {
+		t.Fatal("Expected an error without a managed app that had focus")
+	}
+
+	pausing.focusChanged("Game.exe", true)
+	pausing.focusChanged("explorer.exe", false)
+	if err := PauseUntilBlur(""); err != nil {
+		t.Fatal(err)
+	}
+	if status := CurrentPauseStatus(); status.UntilBlurOf != "Game.exe" {
+		t.Fatalf("Expected to pause until Game.exe loses focus, got %+v", status)
+	}
+
+	// the app didn't have focus when pausing, e.g. because the tray took it
+	pausing.focusChanged("explorer.exe", false)
+	if !CurrentPauseStatus().Paused {
+		t.Fatal("Expected the pause to last until the app had focus")
+	}
+	pausing.focusChanged("Game.exe", true)
+	if !CurrentPauseStatus().Paused {
+		t.Fatal("Expected the pause to last while the app has focus")
+	}
+	pausing.focusChanged("explorer.exe", false)
+	if CurrentPauseStatus().Paused {
+		t.Fatal("Expected the pause to end when the app lost focus")
+	}
+}
+
+func TestApplyPaused(t *testing.T) {
+	usePausing(t)
+	rect := Rect{Left: 100, Top: 100, Right: 900, Bottom: 700}
+	fake := NewFake(Info{Handle: 1, Visible: true, Rect: rect})
+	useBackend(t, fake)
+	useJournal(t, openJournal(t, ""))
+	useApps(t, game(config.StateSettings{}))
+
+	Pause(0)
+	if err := Apply("Game.exe"); !errors.Is(err, ErrPaused) {
+		t.Fatalf("Expected ErrPaused, got %v", err)
+	}
+	if err := applyToWindow(context.Background(), 1, "Game.exe"); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := fake.Info(1); info.Rect != rect {
+		t.Fatalf("Expected the window not to be changed while paused, got %+v", info.Rect)
+	}
+
+	Resume()
+	if err := applyToWindow(context.Background(), 1, "Game.exe"); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := fake.Info(1); info.Rect == rect {
+		t.Fatal("Expected the window to be changed after resuming")
+	}
+}
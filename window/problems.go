@@ -0,0 +1,83 @@
+package window
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/skryvvara/focusframe/process"
+)
+
+// ErrWindowNotFound is returned if a window doesn't exist (anymore) or a process has no main window.
+var ErrWindowNotFound = errors.New("window not found")
+
+// Problem is an error applying the settings of a managed app that the user has to resolve, e.g. an app running as
+// administrator.
+type Problem struct {
+	Executable string
+	Err        error
+}
+
+// Advice explains what the user can do about the problem.
+func (p Problem) Advice() string {
+	switch {
+	case errors.Is(p.Err, process.ErrTargetElevated):
+		return fmt.Sprintf("%s runs as administrator. Restart FocusFrame as administrator or stop running %s as administrator.", p.Executable, p.Executable)
+	case errors.Is(p.Err, process.ErrAccessDenied):
+		return fmt.Sprintf("Windows denies access to %s, this happens e.g. for apps protected by an anti-cheat. Restarting FocusFrame as administrator might help.", p.Executable)
+	}
+	return p.Err.Error()
+}
+
+// OnProblemsChanged is called with all current problems whenever a problem is found or resolved, e.g. to update the
+// tray. It must not block.
+var OnProblemsChanged func([]Problem)
+
+var problems = struct {
+	sync.Mutex
+	byExecutable map[string]Problem
+}{byExecutable: make(map[string]Problem)}
+
+// Problems returns the current problems sorted by executable.
+func Problems() []Problem {
+	problems.Lock()
+	defer problems.Unlock()
+	return sortedProblems()
+}
+
+func sortedProblems() []Problem {
+	list := make([]Problem, 0, len(problems.byExecutable))
+	for _, p := range problems.byExecutable {
+		list = append(list, p)
+	}
+	slices.SortFunc(list, func(a, b Problem) int { return strings.Compare(a.Executable, b.Executable) })
+	return list
+}
+
+// noteResult records the result of applying the settings of the executable. Denied access is recorded as a problem,
+// a successful apply resolves it. Other errors are transient and leave the problems as they are.
+func noteResult(executable string, err error) {
+	problems.Lock()
+	_, known := problems.byExecutable[executable]
+	switch {
+	case errors.Is(err, process.ErrAccessDenied):
+		problems.byExecutable[executable] = Problem{Executable: executable, Err: err}
+		if known {
+			problems.Unlock()
+			return
+		}
+	case err == nil && known:
+		delete(problems.byExecutable, executable)
+	default:
+		problems.Unlock()
+		return
+	}
+	list := sortedProblems()
+	problems.Unlock()
+
+	if OnProblemsChanged != nil {
+		OnProblemsChanged(list)
+	}
+}
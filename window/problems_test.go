@@ -0,0 +1,65 @@
+package window
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/skryvvara/focusframe/config"
+	"github.com/skryvvara/focusframe/process"
+)
+
+// recordProblems clears the problems and records every change for the duration of the test.
+func recordProblems(t *testing.T) *[][]Problem {
+	t.Helper()
+	var changes [][]Problem
+	problems.Lock()
+	previous := problems.byExecutable
+	problems.byExecutable = make(map[string]Problem)
+	problems.Unlock()
+	OnProblemsChanged = func(list []Problem) { changes = append(changes, list) }
+	t.Cleanup(func() {
+		OnProblemsChanged = nil
+		problems.Lock()
+		problems.byExecutable = previous
+		problems.Unlock()
+	})
+	return &changes
+}
+
+func TestNoteResult(t *testing.T) {
+	changes := recordProblems(t)
+	elevated := &process.Error{Op: "move window of", PID: 10, Err: process.ErrTargetElevated}
+
+	noteResult("Game.exe", elevated)
+	noteResult("Game.exe", elevated)
+	noteResult("Game.exe", errors.New("window was not ready"))
+	if len(*changes) != 1 || len(Problems()) != 1 {
+		t.Fatalf("Expected a single problem to be reported once, got %v", *changes)
+	}
+	if advice := Problems()[0].Advice(); !strings.Contains(advice, "administrator") {
+		t.Fatalf("Expected advice to restart as administrator, got %q", advice)
+	}
+
+	noteResult("Game.exe", nil)
+	if len(*changes) != 2 || len((*changes)[1]) != 0 || len(Problems()) != 0 {
+		t.Fatalf("Expected the problem to be resolved, got %v", *changes)
+	}
+}
+
+func TestMoveWindowErrors(t *testing.T) {
+	useBackend(t, NewFake(Info{Handle: 1, PID: 10, Title: "Launcher", ExStyle: WS_EX_TOOLWINDOW, Visible: true}))
+	useApps(t, game(config.StateSettings{}))
+	useIndex(t, NewIndex((&executables{names: map[uint32]string{10: "Game.exe"}}).resolve))
+	useMainWindows(t)
+
+	if err := moveWindow(context.Background(), "Missing.exe"); !errors.Is(err, process.ErrProcessNotFound) {
+		t.Fatalf("Expected ErrProcessNotFound, got %v", err)
+	}
+
+	index.Add(Info{Handle: 1, PID: 10})
+	if err := moveWindow(context.Background(), "Game.exe"); !errors.Is(err, ErrWindowNotFound) {
+		t.Fatalf("Expected ErrWindowNotFound, got %v", err)
+	}
+}
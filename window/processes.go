@@ -0,0 +1,30 @@
+package window
+
+import (
+	"context"
+	"log"
+
+	"github.com/skryvvara/focusframe/process"
+)
+
+// WatchProcesses follows managed apps starting and exiting until the context is cancelled. Their windows are handled
+// by the window events, so this only logs starts and drops the state kept for processes that exited, whose PID might
+// be reused.
+func WatchProcesses(ctx context.Context, w process.Watcher) {
+	for event := range w.Watch(ctx) {
+		switch event.Type {
+		case process.Started:
+			if isManaged(event.Info.Name) {
+				log.Printf("Process started: %s, PID: %d\n", event.Info.Name, event.Info.PID)
+			}
+		case process.Exited:
+			processExited(event.Info.PID)
+		}
+	}
+}
+
+// processExited drops the state kept for the process with the given PID.
+func processExited(pid uint32) {
+	mainWindows.exited(pid)
+	triggers.exited(pid)
+}
@@ -0,0 +1,40 @@
+package window
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/skryvvara/focusframe/process"
+)
+
+func TestWatchProcessesExited(t *testing.T) {
+	useMainWindows(t)
+	mainWindows.track(10, "Game.exe", 1)
+
+	watcher := &process.FakeWatcher{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		WatchProcesses(ctx, watcher)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	tracked := func() bool {
+		mainWindows.mu.Lock()
+		defer mainWindows.mu.Unlock()
+		_, ok := mainWindows.processes[10]
+		return ok
+	}
+	// the watcher might not be subscribed yet, so the exit is repeated until it is handled
+	for deadline := time.Now().Add(time.Second); tracked(); time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the exited process to no longer be tracked")
+		}
+		watcher.Exit(process.Info{PID: 10, Name: "Game.exe"})
+	}
+}
@@ -0,0 +1,75 @@
+package window
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// readyPollInterval is the upper limit for how often the window state is sampled while waiting for it to be ready.
+const readyPollInterval = 50 * time.Millisecond
+
+// WaitReady blocks until the window is ready to be moved, which is the case when
+//   - its rect and style have not changed for rs.StableFor,
+//   - it responds to messages and
+//   - its title matches rs.Title (if set).
+//
+// Returns nil once the window is ready or an error if the window was destroyed, rs.Timeout passed or the context
+// was cancelled.
+func WaitReady(ctx context.Context, h Handle, rs config.ReadySettings) error {
+	var title *regexp.Regexp
+	if rs.Title != "" {
+		var err error
+		if title, err = regexp.Compile(rs.Title); err != nil {
+			return fmt.Errorf("invalid title pattern %q: %v", rs.Title, err)
+		}
+	}
+
+	stableFor := time.Duration(rs.StableFor)
+	poll := min(stableFor/4, readyPollInterval)
+	if poll <= 0 {
+		poll = readyPollInterval
+	}
+
+	timeout := time.NewTimer(time.Duration(rs.Timeout))
+	defer timeout.Stop()
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	var last Info
+	var stableSince time.Time
+	for {
+		info, err := backend.Info(h)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if stableSince.IsZero() || !sameGeometry(info, last) {
+			last = info
+			stableSince = now
+		}
+
+		if now.Sub(stableSince) >= stableFor &&
+			(title == nil || title.MatchString(info.Title)) &&
+			backend.Responding(h) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout.C:
+			return fmt.Errorf("window %#x was not ready after %s", uintptr(h), time.Duration(rs.Timeout))
+		case <-ticker.C:
+		}
+	}
+}
+
+// sameGeometry reports whether the rects and styles of both windows are equal.
+func sameGeometry(a, b Info) bool {
+	return a.Rect == b.Rect && a.ClientRect == b.ClientRect && a.Style == b.Style && a.ExStyle == b.ExStyle
+}
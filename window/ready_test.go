@@ -0,0 +1,91 @@
+package window
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+func readySettings(stableFor time.Duration, title string) config.ReadySettings {
+	return config.ReadySettings{
+		StableFor: config.Duration(stableFor),
+		Title:     title,
+		Timeout:   config.Duration(time.Second),
+	}
+}
+
+func TestWaitReadyStable(t *testing.T) {
+	fake := NewFake(Info{Handle: 1, Visible: true, Rect: Rect{Right: 800, Bottom: 600}})
+	useBackend(t, fake)
+
+	// keep resizing the window for a while, it must not be ready before the resizing stopped
+	resizing := 100 * time.Millisecond
+	go func() {
+		deadline := time.Now().Add(resizing)
+		for i := 1; time.Now().Before(deadline); i++ {
+			fake.Update(1, func(info *Info) { info.Rect.Right = 800 + i })
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+
+	start := time.Now()
+	if err := WaitReady(context.Background(), 1, readySettings(50*time.Millisecond, "")); err != nil {
+		t.Fatalf("Expected window to become ready: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < resizing {
+		t.Fatalf("Window became ready after %s while it was still being resized", elapsed)
+	}
+}
+
+func TestWaitReadyTitle(t *testing.T) {
+	fake := NewFake(Info{Handle: 1, Visible: true, Title: "Loading..."})
+	useBackend(t, fake)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		fake.Update(1, func(info *Info) { info.Title = "Game - Main Menu" })
+	}()
+
+	if err := WaitReady(context.Background(), 1, readySettings(time.Millisecond, "^Game")); err != nil {
+		t.Fatalf("Expected window to become ready: %v", err)
+	}
+}
+
+func TestWaitReadyHungTimeout(t *testing.T) {
+	fake := NewFake(Info{Handle: 1, Visible: true})
+	fake.SetHung(1, true)
+	useBackend(t, fake)
+
+	rs := readySettings(time.Millisecond, "")
+	rs.Timeout = config.Duration(50 * time.Millisecond)
+	if err := WaitReady(context.Background(), 1, rs); err == nil {
+		t.Fatal("Expected a hung window to time out")
+	}
+}
+
+func TestWaitReadyDestroyed(t *testing.T) {
+	fake := NewFake(Info{Handle: 1, Visible: true, Title: "Loading..."})
+	useBackend(t, fake)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		fake.Remove(1)
+	}()
+
+	if err := WaitReady(context.Background(), 1, readySettings(time.Millisecond, "never")); err == nil {
+		t.Fatal("Expected an error for a destroyed window")
+	}
+}
+
+func TestWaitReadyCancelled(t *testing.T) {
+	useBackend(t, NewFake(Info{Handle: 1, Visible: true}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := WaitReady(ctx, 1, readySettings(time.Hour, "")); err != context.Canceled {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package window
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// applyTimeout is the upper limit for a single job, e.g. waiting for a window to become ready and moving it.
+	applyTimeout = 30 * time.Second
+	// applyDebounce is how long a job waits before it runs so bursts of events for a window result in a single job.
+	applyDebounce = 50 * time.Millisecond
+)
+
+var (
+	// scheduler runs all jobs that apply settings to windows outside of the hook callback.
+	scheduler = NewScheduler(applyTimeout, applyDebounce)
+
+	// tracking runs the bookkeeping for window events, it is separate from scheduler so a window event can never
+	// replace a pending apply job of the same window.
+	tracking = NewScheduler(applyTimeout, 0)
+
+	// enforcing runs the re-applies of enforced windows, it is separate from scheduler so a re-apply can never replace
+	// a pending focus job of the same window.
+	enforcing = NewScheduler(applyTimeout, 0)
+)
+
+// Job is a unit of work for a single window, e.g. applying the configured settings after it received focus.
+type Job struct {
+	Handle Handle
+	Name   string // Describes why the job was scheduled, used for logging
+	// CancelOnBlur cancels the job when another window receives focus before it is done.
+	CancelOnBlur bool
+	Run          func(ctx context.Context) error
+}
+
+// Scheduler runs jobs outside of the event hook so a slow window never stalls the message loop.
+//
+// Every window has its own worker, so jobs for the same window run in order while different windows don't block
+// each other. Only the latest job scheduled for a window is kept while it waits, earlier ones are coalesced into it.
+type Scheduler struct {
+	timeout  time.Duration
+	debounce time.Duration
+
+	mu      sync.Mutex
+	workers map[Handle]*worker
+	wg      sync.WaitGroup
+}
+
+// worker holds the pending and running job of a single window.
+type worker struct {
+	pending *Job
+	running *Job
+	cancel  context.CancelFunc
+}
+
+// NewScheduler returns a Scheduler whose jobs are cancelled after timeout and wait for debounce before they run.
+func NewScheduler(timeout time.Duration, debounce time.Duration) *Scheduler {
+	return &Scheduler{
+		timeout:  timeout,
+		debounce: debounce,
+		workers:  make(map[Handle]*worker),
+	}
+}
+
+// Schedule queues the job for its window and returns immediately. A job that is still waiting for the same window
+// is replaced.
+func (s *Scheduler) Schedule(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.workers[job.Handle]
+	if !ok {
+		w = &worker{}
+		s.workers[job.Handle] = w
+		s.wg.Add(1)
+		go s.run(job.Handle, w)
+	}
+
+	w.pending = &job
+}
+
+// FocusChanged cancels all running and pending jobs of other windows that should not outlive their focus.
+func (s *Scheduler) FocusChanged(h Handle) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for handle, w := range s.workers {
+		if handle == h {
+			continue
+		}
+		if w.pending != nil && w.pending.CancelOnBlur {
+			log.Printf("Dropping %s job for window %#x, focus moved on\n", w.pending.Name, handle)
+			w.pending = nil
+		}
+		if w.running != nil && w.running.CancelOnBlur {
+			log.Printf("Cancelling %s job for window %#x, focus moved on\n", w.running.Name, handle)
+			w.cancel()
+		}
+	}
+}
+
+// Wait blocks until all scheduled jobs are done.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// run executes the jobs of a single window until there is nothing left to do.
+func (s *Scheduler) run(h Handle, w *worker) {
+	defer s.wg.Done()
+
+	for {
+		if s.debounce > 0 {
+			time.Sleep(s.debounce)
+		}
+
+		s.mu.Lock()
+		job := w.pending
+		if job == nil {
+			delete(s.workers, h)
+			s.mu.Unlock()
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		w.pending = nil
+		w.running = job
+		w.cancel = cancel
+		s.mu.Unlock()
+
+		if err := job.Run(ctx); err != nil {
+			log.Printf("Error running %s job for window %#x: %v\n", job.Name, h, err)
+		}
+		cancel()
+
+		s.mu.Lock()
+		w.running = nil
+		w.cancel = nil
+		s.mu.Unlock()
+	}
+}
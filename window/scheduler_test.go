@@ -0,0 +1,155 @@
+package window
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// jobLog records the names of jobs in the order they ran.
+type jobLog struct {
+	mu   sync.Mutex
+	runs []string
+}
+
+func (l *jobLog) job(h Handle, name string, run func(ctx context.Context) error) Job {
+	return Job{
+		Handle: h,
+		Name:   name,
+		Run: func(ctx context.Context) error {
+			l.mu.Lock()
+			l.runs = append(l.runs, name)
+			l.mu.Unlock()
+			if run != nil {
+				return run(ctx)
+			}
+			return nil
+		},
+	}
+}
+
+func (l *jobLog) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.runs...)
+}
+
+func TestSchedulerDebounce(t *testing.T) {
+	s := NewScheduler(time.Second, 20*time.Millisecond)
+	log := &jobLog{}
+
+	for _, name := range []string{"first", "second", "third"} {
+		s.Schedule(log.job(1, name, nil))
+	}
+	s.Wait()
+
+	if runs := log.get(); len(runs) != 1 || runs[0] != "third" {
+		t.Fatalf("Expected the burst to be coalesced into the last job, got %v", runs)
+	}
+}
+
+func TestSchedulerOrdering(t *testing.T) {
+	s := NewScheduler(time.Second, 0)
+	log := &jobLog{}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s.Schedule(log.job(1, "slow", func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}))
+	<-started
+
+	// both jobs arrive while the first one runs, only the latest must run afterwards
+	s.Schedule(log.job(1, "stale", nil))
+	s.Schedule(log.job(1, "latest", nil))
+	close(release)
+	s.Wait()
+
+	runs := log.get()
+	if len(runs) != 2 || runs[0] != "slow" || runs[1] != "latest" {
+		t.Fatalf("Expected [slow latest], got %v", runs)
+	}
+}
+
+func TestSchedulerWindowsDontBlockEachOther(t *testing.T) {
+	s := NewScheduler(time.Second, 0)
+	log := &jobLog{}
+
+	release := make(chan struct{})
+	s.Schedule(log.job(1, "hung", func(ctx context.Context) error {
+		<-release
+		return nil
+	}))
+
+	done := make(chan struct{})
+	s.Schedule(log.job(2, "other", func(ctx context.Context) error {
+		close(done)
+		return nil
+	}))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Job of another window was blocked by a hung window")
+	}
+	close(release)
+	s.Wait()
+}
+
+func TestSchedulerCancelOnBlur(t *testing.T) {
+	s := NewScheduler(time.Second, 0)
+	log := &jobLog{}
+
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	job := log.job(1, "focus", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	})
+	job.CancelOnBlur = true
+	s.Schedule(job)
+	<-started
+
+	// a job that must survive focus changes
+	s.Schedule(log.job(1, "replaced", nil))
+
+	s.FocusChanged(1)
+	select {
+	case <-cancelled:
+		t.Fatal("Job was cancelled although its own window received focus")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	s.FocusChanged(2)
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Job was not cancelled after focus moved on")
+	}
+	s.Wait()
+
+	if runs := log.get(); len(runs) != 2 || runs[1] != "replaced" {
+		t.Fatalf("Expected the job without CancelOnBlur to run, got %v", runs)
+	}
+}
+
+func TestSchedulerTimeout(t *testing.T) {
+	s := NewScheduler(20*time.Millisecond, 0)
+
+	var err error
+	s.Schedule(Job{Handle: 1, Name: "slow", Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		err = ctx.Err()
+		return err
+	}})
+	s.Wait()
+
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Expected the job to time out, got %v", err)
+	}
+}
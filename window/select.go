@@ -0,0 +1,52 @@
+package window
+
+import (
+	"time"
+)
+
+// SelectMainWindow picks the window that is most likely the main window of the process with the given PID.
+//
+// Hidden, cloaked and tool windows are never selected. Of the remaining windows, unowned windows are preferred over
+// owned ones (e.g. dialogs), then windows with a caption or the popup style (typical for borderless games), then the
+// window with the largest area and finally the most recently activated one. This way splash screens and launcher
+// windows lose against the real game window as soon as it exists.
+//
+// Returns the selected window and true or an empty Info and false if the process has no suitable window.
+func SelectMainWindow(windows []Info, pid uint32, lastActive map[Handle]time.Time) (Info, bool) {
+	var best Info
+	found := false
+
+	for _, win := range windows {
+		if win.PID != pid || !isMainWindowCandidate(win) || win.Cloaked {
+			continue
+		}
+		if !found || betterMainWindow(win, best, lastActive) {
+			best = win
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+// betterMainWindow reports whether a is a better main window candidate than b.
+func betterMainWindow(a, b Info, lastActive map[Handle]time.Time) bool {
+	if (a.Owner == 0) != (b.Owner == 0) {
+		return a.Owner == 0
+	}
+
+	if hasFrameOrPopup(a) != hasFrameOrPopup(b) {
+		return hasFrameOrPopup(a)
+	}
+
+	if a.Rect.Area() != b.Rect.Area() {
+		return a.Rect.Area() > b.Rect.Area()
+	}
+
+	return lastActive[a.Handle].After(lastActive[b.Handle])
+}
+
+// hasFrameOrPopup reports whether the window has a caption or is a popup window.
+func hasFrameOrPopup(win Info) bool {
+	return win.Style&WS_CAPTION == WS_CAPTION || win.Style&WS_POPUP != 0
+}
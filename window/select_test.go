@@ -0,0 +1,170 @@
+package window
+
+import (
+	"testing"
+	"time"
+)
+
+const (
+	gamePID   = 20
+	splashWnd = Handle(1)
+	mainWnd   = Handle(2)
+)
+
+var (
+	splashInfo = Info{Handle: splashWnd, PID: gamePID, Title: "Loading", Visible: true, Style: WS_POPUP,
+		Rect: Rect{Left: 760, Top: 340, Right: 1160, Bottom: 740}}
+	mainInfo = Info{Handle: mainWnd, PID: gamePID, Title: "Game", Visible: true, Style: WS_CAPTION,
+		Rect: Rect{Right: 1920, Bottom: 1080}}
+)
+
+func TestSelectMainWindow(t *testing.T) {
+	dialog := Info{Handle: 3, PID: gamePID, Title: "Settings", Visible: true, Style: WS_CAPTION, Owner: mainWnd,
+		Rect: Rect{Right: 2560, Bottom: 1440}}
+	cloaked := Info{Handle: 4, PID: gamePID, Title: "Hidden", Visible: true, Cloaked: true, Style: WS_CAPTION,
+		Rect: Rect{Right: 3840, Bottom: 2160}}
+	child := Info{Handle: 5, PID: gamePID, Title: "Child", Visible: true, Rect: Rect{Right: 3840, Bottom: 2160}}
+
+	tests := []struct {
+		name    string
+		windows []Info
+		want    Handle
+	}{
+		{"splashWnd only", []Info{splashInfo}, splashWnd},
+		{"larger mainWnd window wins", []Info{splashInfo, mainInfo}, mainWnd},
+		{"owned dialog loses", []Info{dialog, mainInfo}, mainWnd},
+		{"cloaked window is ignored", []Info{cloaked, mainInfo}, mainWnd},
+		{"window without caption or popup style loses", []Info{child, splashInfo}, splashWnd},
+		{"other processes are ignored", []Info{{Handle: 9, PID: 99, Title: "Other", Visible: true}}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			win, ok := SelectMainWindow(test.windows, gamePID, nil)
+			if ok != (test.want != 0) || win.Handle != test.want {
+				t.Fatalf("Expected window %d, got %d (found: %v)", test.want, win.Handle, ok)
+			}
+		})
+	}
+}
+
+func TestSelectMainWindowMostRecentlyActivated(t *testing.T) {
+	other := mainInfo
+	other.Handle = 3
+
+	now := time.Now()
+	lastActive := map[Handle]time.Time{mainWnd: now.Add(-time.Minute), other.Handle: now}
+
+	if win, _ := SelectMainWindow([]Info{mainInfo, other}, gamePID, lastActive); win.Handle != other.Handle {
+		t.Fatalf("Expected the most recently activated window, got %d", win.Handle)
+	}
+}
+
+// recordApply returns a tracker whose applied windows are recorded in the returned slice.
+func recordApply() (*mainWindowTracker, *[]Handle) {
+	var applied []Handle
+	tracker := newMainWindowTracker(func(h Handle, pid uint32, executable string) {
+		applied = append(applied, h)
+	})
+	return tracker, &applied
+}
+
+func TestMainWindowTrackerSplashThenMain(t *testing.T) {
+	fake := NewFake(splashInfo)
+	useBackend(t, fake)
+
+	tracker, applied := recordApply()
+	tracker.track(gamePID, "game.exe", splashWnd)
+
+	now := time.Now()
+	fake.Add(mainInfo)
+	tracker.handle(Event{Kind: EventShown, Handle: mainWnd, Time: now})
+
+	fake.Remove(splashWnd)
+	tracker.handle(Event{Kind: EventDestroyed, Handle: splashWnd, Time: now})
+
+	if len(*applied) != 1 || (*applied)[0] != mainWnd {
+		t.Fatalf("Expected the mainWnd window to be applied once, got %v", *applied)
+	}
+}
+
+func TestMainWindowTrackerSplashDestroyedFirst(t *testing.T) {
+	fake := NewFake(splashInfo)
+	useBackend(t, fake)
+
+	tracker, applied := recordApply()
+	tracker.track(gamePID, "game.exe", splashWnd)
+
+	now := time.Now()
+	fake.Remove(splashWnd)
+	tracker.handle(Event{Kind: EventDestroyed, Handle: splashWnd, Time: now})
+
+	if len(*applied) != 0 {
+		t.Fatalf("Expected nothing to be applied without a window, got %v", *applied)
+	}
+
+	fake.Add(mainInfo)
+	tracker.handle(Event{Kind: EventShown, Handle: mainWnd, Time: now.Add(2 * time.Second)})
+
+	if len(*applied) != 1 || (*applied)[0] != mainWnd {
+		t.Fatalf("Expected the mainWnd window to be applied once, got %v", *applied)
+	}
+}
+
+// listingBackend calls listed whenever all windows are listed.
+type listingBackend struct {
+	*Fake
+	listed func()
+}
+
+func (b listingBackend) Windows() ([]Info, error) {
+	b.listed()
+	return b.Fake.Windows()
+}
+
+func TestMainWindowTrackerExitedWhileListing(t *testing.T) {
+	fake := NewFake(splashInfo)
+	tracker, applied := recordApply()
+	useBackend(t, listingBackend{Fake: fake, listed: func() { tracker.exited(gamePID) }})
+	useIndex(t, NewIndex((&executables{}).resolve))
+	tracker.track(gamePID, "game.exe", splashWnd)
+
+	fake.Add(mainInfo)
+	tracker.handle(Event{Kind: EventShown, Handle: mainWnd, Time: time.Now()})
+
+	if len(*applied) != 0 {
+		t.Fatalf("Expected nothing to be applied for a process that exited, got %v", *applied)
+	}
+}
+
+func TestMainWindowTrackerGracePeriod(t *testing.T) {
+	fake := NewFake(splashInfo)
+	useBackend(t, fake)
+
+	tracker, applied := recordApply()
+	tracker.track(gamePID, "game.exe", splashWnd)
+
+	now := time.Now()
+	fake.Remove(splashWnd)
+	tracker.handle(Event{Kind: EventDestroyed, Handle: splashWnd, Time: now})
+
+	// a window shown long after the process lost its mainWnd window likely belongs to a new process with the same PID
+	fake.Add(mainInfo)
+	tracker.handle(Event{Kind: EventShown, Handle: mainWnd, Time: now.Add(mainWindowGrace + time.Second)})
+
+	if len(*applied) != 0 {
+		t.Fatalf("Expected nothing to be applied after the grace period, got %v", *applied)
+	}
+}
+
+func TestMainWindowTrackerIgnoresUntracked(t *testing.T) {
+	fake := NewFake(mainInfo)
+	useBackend(t, fake)
+
+	tracker, applied := recordApply()
+	tracker.handle(Event{Kind: EventShown, Handle: mainWnd, Time: time.Now()})
+
+	if len(*applied) != 0 {
+		t.Fatalf("Expected untracked processes to be ignored, got %v", *applied)
+	}
+}
@@ -0,0 +1,57 @@
+package window
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/skryvvara/focusframe/config"
+	"github.com/skryvvara/focusframe/input"
+)
+
+// sendKeys sends key presses to the foreground window, tests replace it to record the keys instead.
+var sendKeys = input.SendKeys
+
+// skipReason returns why the settings can't be applied to the window in its current state or "" if they can.
+func skipReason(info Info, ss config.StateSettings) string {
+	switch {
+	case info.Minimized:
+		// restoring the window activates it, which applies the settings again
+		return "it is minimized and will be applied once it is restored"
+	case info.Maximized && !ss.Unmaximize:
+		return "it is maximized, enable unmaximize in the state settings to restore it first"
+	}
+	return ""
+}
+
+// prepareState gets the window into a state the settings can be applied in: maximized windows are restored and
+// apps in exclusive fullscreen are sent the configured keys to leave it.
+func prepareState(ctx context.Context, info Info, executable string, ss config.StateSettings) error {
+	if info.Maximized {
+		log.Printf("Restoring maximized window %#x of %s\n", info.Handle, executable)
+		return backend.SetShowState(info.Handle, ShowNormal)
+	}
+
+	if !info.Fullscreen || ss.FullscreenKeys == "" {
+		return nil
+	}
+	// a window FocusFrame already made borderless and as large as its monitor looks just like fullscreen
+	ws := appSettings(executable)
+	if verifyGeometry(info, wantedRect(ws), ws.SizeMode) == nil {
+		return nil
+	}
+
+	seq, err := input.ParseSequence(ss.FullscreenKeys)
+	if err != nil {
+		return err
+	}
+	if backend.Foreground() != info.Handle {
+		return fmt.Errorf("window is in fullscreen but doesn't have focus, can't send %s", ss.FullscreenKeys)
+	}
+
+	log.Printf("Window %#x of %s is in fullscreen, sending %s\n", info.Handle, executable, ss.FullscreenKeys)
+	if err := sendKeys(seq); err != nil {
+		return err
+	}
+	return WaitReady(ctx, info.Handle, config.GetReadySettings(executable))
+}
@@ -0,0 +1,116 @@
+package window
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/skryvvara/focusframe/config"
+	"github.com/skryvvara/focusframe/input"
+)
+
+// useApps replaces the managed apps of the config for the duration of the test.
+func useApps(t *testing.T, apps ...config.ManagedApp) {
+	t.Helper()
+	previous := config.Config.ManagedApps
+	config.Config.ManagedApps = make(map[string]config.ManagedApp)
+	for _, app := range apps {
+		config.Config.ManagedApps[app.Executable] = app
+	}
+	t.Cleanup(func() { config.Config.ManagedApps = previous })
+}
+
+// recordKeys replaces sendKeys for the duration of the test and returns the sent sequences.
+func recordKeys(t *testing.T) *[]input.Sequence {
+	var sent []input.Sequence
+	previous := sendKeys
+	sendKeys = func(seq input.Sequence) error {
+		sent = append(sent, seq)
+		return nil
+	}
+	t.Cleanup(func() { sendKeys = previous })
+	return &sent
+}
+
+// game is a managed app with a quick readiness check.
+func game(state config.StateSettings) config.ManagedApp {
+	return config.ManagedApp{
+		Executable: "Game.exe",
+		Dimensions: config.WindowSettings{Width: 1920, Height: 1080},
+		Ready:      config.ReadySettings{StableFor: config.Duration(time.Millisecond), Timeout: config.Duration(time.Second)},
+		State:      state,
+	}
+}
+
+func TestApplySkipsMinimizedAndMaximized(t *testing.T) {
+	rect := Rect{Left: 100, Top: 100, Right: 900, Bottom: 700}
+	fake := NewFake(
+		Info{Handle: 1, Visible: true, Minimized: true, Rect: rect},
+		Info{Handle: 2, Visible: true, Maximized: true, Rect: rect},
+	)
+	useBackend(t, fake)
+	useJournal(t, openJournal(t, ""))
+	useApps(t, game(config.StateSettings{}))
+
+	for _, h := range []Handle{1, 2} {
+		if err := applySettings(context.Background(), h, "Game.exe"); err != nil {
+			t.Fatal(err)
+		}
+		if info, _ := fake.Info(h); info.Rect != rect {
+			t.Fatalf("Expected window %d to be skipped, got %+v", h, info.Rect)
+		}
+	}
+	if windows := journal.Windows(); len(windows) != 0 {
+		t.Fatalf("Expected skipped windows not to be journaled, got %+v", windows)
+	}
+}
+
+func TestApplyUnmaximizes(t *testing.T) {
+	fake := NewFake(Info{Handle: 1, Visible: true, Maximized: true, Rect: Rect{Right: 2560, Bottom: 1440}})
+	useBackend(t, fake)
+	useJournal(t, openJournal(t, ""))
+	useApps(t, game(config.StateSettings{Unmaximize: true}))
+
+	if err := applySettings(context.Background(), 1, "Game.exe"); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := fake.Info(1)
+	if info.Maximized || info.Rect != (Rect{Right: 1920, Bottom: 1080}) {
+		t.Fatalf("Expected the window to be restored and resized, got %+v", info)
+	}
+	if windows := journal.Windows(); len(windows) != 1 || !windows[0].Maximized {
+		t.Fatalf("Expected the window to be journaled as maximized, got %+v", windows)
+	}
+}
+
+func TestPrepareStateFullscreen(t *testing.T) {
+	monitor := Monitor{Rect: Rect{Right: 2560, Bottom: 1440}}
+	fullscreen := Info{Handle: 1, Visible: true, Fullscreen: true, Rect: monitor.Rect, Monitor: monitor}
+	fake := NewFake(fullscreen)
+	useBackend(t, fake)
+	state := config.StateSettings{FullscreenKeys: "Alt+Enter"}
+	useApps(t, game(state))
+	sent := recordKeys(t)
+
+	// keys can only be sent to the focused window
+	if err := prepareState(context.Background(), fullscreen, "Game.exe", state); err == nil {
+		t.Fatal("Expected an error for a window without focus")
+	}
+
+	fake.SetForeground(1)
+	if err := prepareState(context.Background(), fullscreen, "Game.exe", state); err != nil {
+		t.Fatal(err)
+	}
+	if len(*sent) != 1 || len((*sent)[0]) != 1 || len((*sent)[0][0]) != 2 || (*sent)[0][0][0] != input.VK_MENU {
+		t.Fatalf("Expected Alt+Enter to be sent, got %v", *sent)
+	}
+
+	// a window FocusFrame made as large as the monitor is not in exclusive fullscreen
+	useApps(t, config.ManagedApp{Executable: "Game.exe", Dimensions: config.WindowSettings{Width: 2560, Height: 1440}, State: state})
+	if err := prepareState(context.Background(), fullscreen, "Game.exe", state); err != nil {
+		t.Fatal(err)
+	}
+	if len(*sent) != 1 {
+		t.Fatalf("Expected no keys for a borderless window, got %v", *sent)
+	}
+}
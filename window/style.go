@@ -0,0 +1,161 @@
+package window
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// Window styles, see https://learn.microsoft.com/en-us/windows/win32/winmsg/window-styles
+const (
+	WS_OVERLAPPED   = 0x00000000
+	WS_POPUP        = 0x80000000
+	WS_CHILD        = 0x40000000
+	WS_MINIMIZE     = 0x20000000
+	WS_VISIBLE      = 0x10000000
+	WS_DISABLED     = 0x08000000
+	WS_CLIPSIBLINGS = 0x04000000
+	WS_CLIPCHILDREN = 0x02000000
+	WS_MAXIMIZE     = 0x01000000
+	WS_CAPTION      = 0x00C00000 // Title bar
+	WS_BORDER       = 0x00800000
+	WS_DLGFRAME     = 0x00400000
+	WS_VSCROLL      = 0x00200000
+	WS_HSCROLL      = 0x00100000
+	WS_SYSMENU      = 0x00080000
+	WS_THICKFRAME   = 0x00040000 // Resizable border
+	WS_MINIMIZEBOX  = 0x00020000
+	WS_MAXIMIZEBOX  = 0x00010000
+
+	WS_OVERLAPPEDWINDOW = WS_OVERLAPPED | WS_CAPTION | WS_SYSMENU | WS_THICKFRAME | WS_MINIMIZEBOX | WS_MAXIMIZEBOX
+	WS_POPUPWINDOW      = WS_POPUP | WS_BORDER | WS_SYSMENU
+)
+
+// Extended window styles, see https://learn.microsoft.com/en-us/windows/win32/winmsg/extended-window-styles
+const (
+	WS_EX_DLGMODALFRAME = 0x00000001
+	WS_EX_TOPMOST       = 0x00000008
+	WS_EX_TRANSPARENT   = 0x00000020
+	WS_EX_TOOLWINDOW    = 0x00000080
+	WS_EX_WINDOWEDGE    = 0x00000100
+	WS_EX_CLIENTEDGE    = 0x00000200
+	WS_EX_STATICEDGE    = 0x00020000
+	WS_EX_APPWINDOW     = 0x00040000
+	WS_EX_LAYERED       = 0x00080000
+	WS_EX_COMPOSITED    = 0x02000000
+	WS_EX_NOACTIVATE    = 0x08000000
+
+	WS_EX_OVERLAPPEDWINDOW = WS_EX_WINDOWEDGE | WS_EX_CLIENTEDGE
+)
+
+// styleNames maps the names of all styles that can be used in the config to their value.
+var styleNames = map[string]uint32{
+	"WS_OVERLAPPED":       WS_OVERLAPPED,
+	"WS_POPUP":            WS_POPUP,
+	"WS_CHILD":            WS_CHILD,
+	"WS_MINIMIZE":         WS_MINIMIZE,
+	"WS_VISIBLE":          WS_VISIBLE,
+	"WS_DISABLED":         WS_DISABLED,
+	"WS_CLIPSIBLINGS":     WS_CLIPSIBLINGS,
+	"WS_CLIPCHILDREN":     WS_CLIPCHILDREN,
+	"WS_MAXIMIZE":         WS_MAXIMIZE,
+	"WS_CAPTION":          WS_CAPTION,
+	"WS_BORDER":           WS_BORDER,
+	"WS_DLGFRAME":         WS_DLGFRAME,
+	"WS_VSCROLL":          WS_VSCROLL,
+	"WS_HSCROLL":          WS_HSCROLL,
+	"WS_SYSMENU":          WS_SYSMENU,
+	"WS_THICKFRAME":       WS_THICKFRAME,
+	"WS_MINIMIZEBOX":      WS_MINIMIZEBOX,
+	"WS_MAXIMIZEBOX":      WS_MAXIMIZEBOX,
+	"WS_OVERLAPPEDWINDOW": WS_OVERLAPPEDWINDOW,
+	"WS_POPUPWINDOW":      WS_POPUPWINDOW,
+}
+
+// exStyleNames maps the names of all extended styles that can be used in the config to their value.
+var exStyleNames = map[string]uint32{
+	"WS_EX_DLGMODALFRAME":    WS_EX_DLGMODALFRAME,
+	"WS_EX_TOPMOST":          WS_EX_TOPMOST,
+	"WS_EX_TRANSPARENT":      WS_EX_TRANSPARENT,
+	"WS_EX_TOOLWINDOW":       WS_EX_TOOLWINDOW,
+	"WS_EX_WINDOWEDGE":       WS_EX_WINDOWEDGE,
+	"WS_EX_CLIENTEDGE":       WS_EX_CLIENTEDGE,
+	"WS_EX_STATICEDGE":       WS_EX_STATICEDGE,
+	"WS_EX_APPWINDOW":        WS_EX_APPWINDOW,
+	"WS_EX_LAYERED":          WS_EX_LAYERED,
+	"WS_EX_COMPOSITED":       WS_EX_COMPOSITED,
+	"WS_EX_NOACTIVATE":       WS_EX_NOACTIVATE,
+	"WS_EX_OVERLAPPEDWINDOW": WS_EX_OVERLAPPEDWINDOW,
+}
+
+// StyleChange describes how the style of a window is changed.
+//
+// Decorations is the portable part, a backend maps it to whatever its windowing system uses for title bars and
+// borders. The flags are only understood by the win32 backend.
+type StyleChange struct {
+	Decorations     bool // Keep the title bar and borders, otherwise they are removed
+	Add, Remove     uint32
+	AddEx, RemoveEx uint32
+	FrameChanged    bool // Notify the window that its frame changed, so it recalculates its client area
+}
+
+// ParseStyle converts the style settings of the config into a StyleChange.
+//
+// Returns either the StyleChange or an error if a flag name is unknown.
+func ParseStyle(ss config.StyleSettings) (StyleChange, error) {
+	change := StyleChange{Decorations: ss.Decorations, FrameChanged: ss.FrameChanged}
+
+	var err error
+	if change.Add, err = parseFlags(ss.Add, styleNames, "WS_"); err != nil {
+		return change, err
+	}
+	if change.Remove, err = parseFlags(ss.Remove, styleNames, "WS_"); err != nil {
+		return change, err
+	}
+	if change.AddEx, err = parseFlags(ss.AddEx, exStyleNames, "WS_EX_"); err != nil {
+		return change, err
+	}
+	if change.RemoveEx, err = parseFlags(ss.RemoveEx, exStyleNames, "WS_EX_"); err != nil {
+		return change, err
+	}
+	return change, nil
+}
+
+// parseFlags combines the flags with the given names. Names are case-insensitive and the prefix may be left out,
+// e.g. "caption" for "WS_CAPTION".
+func parseFlags(names []string, flags map[string]uint32, prefix string) (uint32, error) {
+	var combined uint32
+	for _, name := range names {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		flag, ok := flags[name]
+		if !ok {
+			flag, ok = flags[prefix+name]
+		}
+		if !ok {
+			return 0, fmt.Errorf("unknown window style %q", name)
+		}
+		combined |= flag
+	}
+	return combined, nil
+}
+
+// Apply returns the style and extended style of a window after the change.
+func (c StyleChange) Apply(style uint32, exStyle uint32) (uint32, uint32) {
+	if !c.Decorations {
+		style = (style &^ (WS_CAPTION | WS_THICKFRAME)) | WS_POPUP | WS_VISIBLE
+	}
+	style = (style &^ c.Remove) | c.Add
+	exStyle = (exStyle &^ c.RemoveEx) | c.AddEx
+	return style, exStyle
+}
+
+// SetStyle changes the style of the window according to the style settings of the executable.
+func SetStyle(h Handle, executable string) error {
+	app, _ := config.GetManagedApp(executable)
+	change, err := ParseStyle(app.Style)
+	if err != nil {
+		return err
+	}
+	return backend.SetStyle(h, change)
+}
@@ -0,0 +1,79 @@
+package window
+
+import (
+	"testing"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+func TestParseStyle(t *testing.T) {
+	change, err := ParseStyle(config.StyleSettings{
+		Add:      []string{"WS_MINIMIZEBOX", "sysmenu"},
+		Remove:   []string{"ws_popup"},
+		RemoveEx: []string{"WS_EX_DLGMODALFRAME", "CLIENTEDGE"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if change.Add != WS_MINIMIZEBOX|WS_SYSMENU || change.Remove != WS_POPUP {
+		t.Fatalf("Unexpected styles %+v", change)
+	}
+	if change.AddEx != 0 || change.RemoveEx != WS_EX_DLGMODALFRAME|WS_EX_CLIENTEDGE {
+		t.Fatalf("Unexpected extended styles %+v", change)
+	}
+}
+
+func TestParseStyleUnknown(t *testing.T) {
+	if _, err := ParseStyle(config.StyleSettings{Add: []string{"WS_FANCY"}}); err == nil {
+		t.Fatal("Expected an error for an unknown style")
+	}
+	// extended styles are not valid styles and vice versa
+	if _, err := ParseStyle(config.StyleSettings{Remove: []string{"WS_EX_CLIENTEDGE"}}); err == nil {
+		t.Fatal("Expected an error for an extended style in the style list")
+	}
+	if _, err := ParseStyle(config.StyleSettings{AddEx: []string{"WS_CAPTION"}}); err == nil {
+		t.Fatal("Expected an error for a style in the extended style list")
+	}
+}
+
+func TestStyleChangeApply(t *testing.T) {
+	const overlapped = WS_OVERLAPPEDWINDOW | WS_VISIBLE | WS_CLIPSIBLINGS
+	const edges = WS_EX_WINDOWEDGE | WS_EX_CLIENTEDGE
+
+	tests := []struct {
+		name    string
+		change  StyleChange
+		style   uint32
+		exStyle uint32
+	}{
+		{"default", StyleChange{}, WS_POPUP | WS_VISIBLE | WS_CLIPSIBLINGS | WS_SYSMENU | WS_MINIMIZEBOX | WS_MAXIMIZEBOX, edges},
+		{"decorations", StyleChange{Decorations: true}, overlapped, edges},
+		{"remove popup", StyleChange{Remove: WS_POPUP}, WS_VISIBLE | WS_CLIPSIBLINGS | WS_SYSMENU | WS_MINIMIZEBOX | WS_MAXIMIZEBOX, edges},
+		{"extended", StyleChange{Decorations: true, RemoveEx: WS_EX_CLIENTEDGE, AddEx: WS_EX_TOPMOST}, overlapped, WS_EX_WINDOWEDGE | WS_EX_TOPMOST},
+	}
+
+	for _, test := range tests {
+		style, exStyle := test.change.Apply(overlapped, edges)
+		if style != test.style || exStyle != test.exStyle {
+			t.Errorf("%s: expected %#x/%#x, got %#x/%#x", test.name, test.style, test.exStyle, style, exStyle)
+		}
+	}
+}
+
+func TestSetStyle(t *testing.T) {
+	fake := NewFake(Info{Handle: 1, Visible: true, Style: WS_OVERLAPPEDWINDOW, ExStyle: WS_EX_DLGMODALFRAME})
+	useBackend(t, fake)
+
+	useApps(t, config.ManagedApp{
+		Executable: "Game.exe",
+		Style:      config.StyleSettings{Decorations: true, RemoveEx: []string{"WS_EX_DLGMODALFRAME"}},
+	})
+
+	if err := SetStyle(1, "Game.exe"); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := fake.Info(1); info.Style != WS_OVERLAPPEDWINDOW || info.ExStyle != 0 {
+		t.Fatalf("Expected the frame to be kept and the modal frame removed, got %#x/%#x", info.Style, info.ExStyle)
+	}
+}
@@ -0,0 +1,156 @@
+package window
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// triggerStartup applies the settings of managed apps that were already running when FocusFrame started. It is
+// allowed for all apps that are not applied manually only.
+const triggerStartup = "startup"
+
+// triggerReplaced applies the settings of a managed app to the window that replaced its main window, e.g. the game
+// window following a splash screen. Like triggerStartup it is allowed for all apps that are not applied manually.
+const triggerReplaced = "main window replaced"
+
+// triggers decides which events apply the settings of managed apps.
+var triggers = newApplyTriggers(config.GetApplyOn)
+
+// applyTriggers decides which events apply the settings of a managed app according to its apply_on setting, see
+// config.ApplyOnShown and the other triggers.
+type applyTriggers struct {
+	mu      sync.Mutex
+	titles  map[Handle]string // last known title of every window that was seen
+	once    map[uint32]string // executables of the processes of apps applied once that were applied to
+	applyOn func(executable string) []string
+}
+
+func newApplyTriggers(applyOn func(executable string) []string) *applyTriggers {
+	return &applyTriggers{
+		titles:  make(map[Handle]string),
+		once:    make(map[uint32]string),
+		applyOn: applyOn,
+	}
+}
+
+// allow reports whether the trigger applies the settings of the process. For apps applied once, no trigger is allowed
+// for a process anymore after the settings were applied to it, see applied.
+func (t *applyTriggers) allow(pid uint32, executable string, trigger string) bool {
+	on := t.applyOn(executable)
+	once := slices.Contains(on, config.ApplyOnOnce)
+
+	allowed := slices.Contains(on, trigger)
+	switch trigger {
+	case triggerStartup, triggerReplaced:
+		allowed = slices.ContainsFunc(on, func(s string) bool { return s != config.ApplyOnManual })
+	case config.ApplyOnShown, config.ApplyOnFocus:
+		// once on its own applies on the first window shown or focused
+		allowed = allowed || once && !slices.ContainsFunc(on, func(s string) bool {
+			return s == config.ApplyOnShown || s == config.ApplyOnFocus || s == config.ApplyOnTitle
+		})
+	}
+	if !allowed || !once {
+		return allowed
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// a different executable means the PID was reused
+	return t.once[pid] != executable
+}
+
+// applied records that the settings were applied to the process, so an app applied once is not applied to it again.
+func (t *applyTriggers) applied(pid uint32, executable string) {
+	if !slices.Contains(t.applyOn(executable), config.ApplyOnOnce) {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.once[pid] = executable
+}
+
+// exited forgets the process, whose PID might be reused.
+func (t *applyTriggers) exited(pid uint32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.once, pid)
+}
+
+// windowChanged records the current state of the window and returns the trigger the change corresponds to, i.e.
+// config.ApplyOnShown for a window that was not seen before or config.ApplyOnTitle if its title changed, otherwise "".
+func (t *applyTriggers) windowChanged(info Info) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	title, seen := t.titles[info.Handle]
+	t.titles[info.Handle] = info.Title
+	switch {
+	case !seen:
+		return config.ApplyOnShown
+	case title != info.Title:
+		return config.ApplyOnTitle
+	}
+	return ""
+}
+
+// markSeen records windows that already existed, so they are not taken for newly shown windows.
+func (t *applyTriggers) markSeen(windows []Info) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, info := range windows {
+		t.titles[info.Handle] = info.Title
+	}
+}
+
+// forget removes a destroyed window.
+func (t *applyTriggers) forget(h Handle) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.titles, h)
+}
+
+// applyOnChange applies the settings of a managed app when its main window was shown or its title changed, if the
+// app is applied on these triggers.
+func applyOnChange(info Info) {
+	if !isMainWindowCandidate(info) {
+		return
+	}
+	pid, executable, ok := index.Owner(info.Handle)
+	if !ok || !isManaged(executable) {
+		return
+	}
+
+	trigger := triggers.windowChanged(info)
+	// checked before the trigger, so apps applied once are applied after resuming
+	if trigger == "" || pausing.paused() || mainWindowOf(pid) != info.Handle || !triggers.allow(pid, executable, trigger) {
+		return
+	}
+	scheduleApply(info.Handle, pid, executable, trigger)
+}
+
+// applyRunning applies the settings of all managed apps that are already running, e.g. when FocusFrame starts.
+func applyRunning() {
+	for _, executable := range config.ManagedExecutables() {
+		for _, pid := range index.ProcessIDs(executable) {
+			h := mainWindowOf(pid)
+			if h == 0 || pausing.paused() || !triggers.allow(pid, executable, triggerStartup) {
+				continue
+			}
+			scheduleApply(h, pid, executable, triggerStartup)
+		}
+	}
+}
+
+// scheduleApply applies the settings of the executable to the main window of the process outside of the caller.
+func scheduleApply(h Handle, pid uint32, executable string, name string) {
+	mainWindows.track(pid, executable, h)
+	scheduler.Schedule(Job{
+		Handle: h,
+		Name:   name,
+		Run: func(ctx context.Context) error {
+			return applyToWindow(ctx, h, executable)
+		},
+	})
+}
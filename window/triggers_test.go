@@ -0,0 +1,221 @@
+package window
+
+import (
+	"context"
+	"testing"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// useScheduler replaces the package scheduler for the duration of the test.
+func useScheduler(t *testing.T, s *Scheduler) {
+	t.Helper()
+	previous := scheduler
+	scheduler = s
+	t.Cleanup(func() { scheduler = previous })
+}
+
+// useMainWindows replaces the package main window tracker with an empty one for the duration of the test.
+func useMainWindows(t *testing.T) {
+	t.Helper()
+	previous := mainWindows
+	mainWindows = newMainWindowTracker(previous.apply)
+	t.Cleanup(func() { mainWindows = previous })
+}
+
+// useTriggers replaces the package triggers with ones that have not seen any window for the duration of the test.
+func useTriggers(t *testing.T) {
+	t.Helper()
+	previous := triggers
+	triggers = newApplyTriggers(config.GetApplyOn)
+	t.Cleanup(func() { triggers = previous })
+}
+
+func triggersFor(on ...string) *applyTriggers {
+	return newApplyTriggers(func(executable string) []string { return on })
+}
+
+func TestApplyTriggersAllow(t *testing.T) {
+	tests := []struct {
+		on      []string
+		allowed []string
+		denied  []string
+	}{
+		{[]string{config.ApplyOnFocus}, []string{config.ApplyOnFocus, triggerStartup, triggerReplaced}, []string{config.ApplyOnShown, config.ApplyOnTitle}},
+		{[]string{config.ApplyOnShown, config.ApplyOnTitle}, []string{config.ApplyOnShown, config.ApplyOnTitle, triggerReplaced}, []string{config.ApplyOnFocus}},
+		{[]string{config.ApplyOnManual}, nil, []string{config.ApplyOnFocus, config.ApplyOnShown, triggerStartup, triggerReplaced}},
+	}
+
+	for _, test := range tests {
+		triggers := triggersFor(test.on...)
+		for _, trigger := range test.allowed {
+			if !triggers.allow(1, "Game.exe", trigger) {
+				t.Errorf("%v: expected %s to be allowed", test.on, trigger)
+			}
+		}
+		for _, trigger := range test.denied {
+			if triggers.allow(1, "Game.exe", trigger) {
+				t.Errorf("%v: expected %s to be denied", test.on, trigger)
+			}
+		}
+	}
+}
+
+func TestApplyTriggersOnce(t *testing.T) {
+	triggers := triggersFor(config.ApplyOnOnce)
+	if !triggers.allow(1, "Game.exe", config.ApplyOnShown) || !triggers.allow(1, "Game.exe", config.ApplyOnShown) {
+		t.Fatal("Expected shown windows to be applied until the settings were applied")
+	}
+	triggers.applied(1, "Game.exe")
+	if triggers.allow(1, "Game.exe", config.ApplyOnFocus) || triggers.allow(1, "Game.exe", triggerStartup) {
+		t.Fatal("Expected the process not to be applied again")
+	}
+	if !triggers.allow(2, "Game.exe", config.ApplyOnFocus) {
+		t.Fatal("Expected a new process to be applied")
+	}
+	if !triggers.allow(1, "Other.exe", config.ApplyOnFocus) {
+		t.Fatal("Expected a reused PID to be applied")
+	}
+	triggers.exited(1)
+	if !triggers.allow(1, "Game.exe", config.ApplyOnFocus) || len(triggers.once) != 0 {
+		t.Fatal("Expected an exited process to be forgotten")
+	}
+
+	// once with other triggers limits them
+	triggers = triggersFor(config.ApplyOnOnce, config.ApplyOnFocus)
+	if triggers.allow(1, "Game.exe", config.ApplyOnShown) {
+		t.Fatal("Expected shown to be denied")
+	}
+	if !triggers.allow(1, "Game.exe", config.ApplyOnFocus) {
+		t.Fatal("Expected focus to be allowed")
+	}
+	triggers.applied(1, "Game.exe")
+	if triggers.allow(1, "Game.exe", config.ApplyOnFocus) {
+		t.Fatal("Expected focus to be denied after the settings were applied")
+	}
+
+	// apps not applied once are not remembered
+	triggers = triggersFor(config.ApplyOnFocus)
+	triggers.applied(1, "Game.exe")
+	if len(triggers.once) != 0 {
+		t.Fatalf("Expected no process to be remembered, got %v", triggers.once)
+	}
+}
+
+func TestApplyTriggersWindowChanged(t *testing.T) {
+	triggers := triggersFor()
+	triggers.markSeen([]Info{{Handle: 1, Title: "Launcher"}})
+
+	if trigger := triggers.windowChanged(Info{Handle: 1, Title: "Launcher"}); trigger != "" {
+		t.Fatalf("Expected no trigger for an existing window, got %q", trigger)
+	}
+	if trigger := triggers.windowChanged(Info{Handle: 2, Title: "Loading"}); trigger != config.ApplyOnShown {
+		t.Fatalf("Expected a new window to be shown, got %q", trigger)
+	}
+	if trigger := triggers.windowChanged(Info{Handle: 2, Title: "Game"}); trigger != config.ApplyOnTitle {
+		t.Fatalf("Expected a title change, got %q", trigger)
+	}
+
+	triggers.forget(2)
+	if trigger := triggers.windowChanged(Info{Handle: 2, Title: "Game"}); trigger != config.ApplyOnShown {
+		t.Fatalf("Expected a reused handle to be shown, got %q", trigger)
+	}
+}
+
+func TestApplyOnShown(t *testing.T) {
+	background := game(config.StateSettings{})
+	background.ApplyOn = []string{config.ApplyOnShown}
+	focused := game(config.StateSettings{})
+	focused.Executable = "Focus.exe"
+
+	fake := NewFake()
+	useBackend(t, fake)
+	useApps(t, background, focused)
+	useIndex(t, NewIndex((&executables{names: map[uint32]string{10: "Game.exe", 20: "Focus.exe"}}).resolve))
+	useJournal(t, openJournal(t, ""))
+	useScheduler(t, NewScheduler(applyTimeout, 0))
+	useMainWindows(t)
+	useTriggers(t)
+
+	// both games start in the background
+	initial := Rect{Right: 800, Bottom: 600}
+	fake.Add(Info{Handle: 1, PID: 10, Title: "Game", Visible: true, Style: WS_CAPTION, Rect: initial})
+	fake.Add(Info{Handle: 2, PID: 20, Title: "Focus", Visible: true, Style: WS_CAPTION, Rect: initial})
+	syncWindow(Event{Kind: EventShown, Handle: 1})
+	syncWindow(Event{Kind: EventShown, Handle: 2})
+	scheduler.Wait()
+
+	if info, _ := fake.Info(1); info.Rect != (Rect{Right: 1920, Bottom: 1080}) {
+		t.Fatalf("Expected the game applied on shown to be moved, got %+v", info.Rect)
+	}
+	if info, _ := fake.Info(2); info.Rect != initial {
+		t.Fatalf("Expected the game applied on focus to stay, got %+v", info.Rect)
+	}
+}
+
+func TestApplyRunning(t *testing.T) {
+	manual := game(config.StateSettings{})
+	manual.Executable = "Manual.exe"
+	manual.ApplyOn = []string{config.ApplyOnManual}
+
+	initial := Rect{Right: 800, Bottom: 600}
+	fake := NewFake(
+		Info{Handle: 1, PID: 10, Title: "Game", Visible: true, Style: WS_CAPTION, Rect: initial},
+		Info{Handle: 2, PID: 20, Title: "Manual", Visible: true, Style: WS_CAPTION, Rect: initial},
+	)
+	useBackend(t, fake)
+	useApps(t, game(config.StateSettings{}), manual)
+	useIndex(t, NewIndex((&executables{names: map[uint32]string{10: "Game.exe", 20: "Manual.exe"}}).resolve))
+	useJournal(t, openJournal(t, ""))
+	useScheduler(t, NewScheduler(applyTimeout, 0))
+	useMainWindows(t)
+
+	windows, _ := fake.Windows()
+	index.Fill(windows)
+	applyRunning()
+	scheduler.Wait()
+
+	if info, _ := fake.Info(1); info.Rect != (Rect{Right: 1920, Bottom: 1080}) {
+		t.Fatalf("Expected the running game to be moved, got %+v", info.Rect)
+	}
+	if info, _ := fake.Info(2); info.Rect != initial {
+		t.Fatalf("Expected the manual app to stay, got %+v", info.Rect)
+	}
+}
+
+func TestApplyOnceSkipped(t *testing.T) {
+	once := game(config.StateSettings{})
+	once.ApplyOn = []string{config.ApplyOnOnce}
+
+	fake := NewFake(Info{Handle: 1, PID: 10, Title: "Game", Visible: true, Style: WS_CAPTION, Minimized: true, Rect: Rect{Right: 800, Bottom: 600}})
+	useBackend(t, fake)
+	useApps(t, once)
+	useIndex(t, NewIndex((&executables{names: map[uint32]string{10: "Game.exe"}}).resolve))
+	useJournal(t, openJournal(t, ""))
+	useMainWindows(t)
+	useTriggers(t)
+
+	// the minimized window is skipped, which doesn't use up the single application
+	if err := applyForeground(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.SetShowState(1, ShowNormal); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyForeground(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := fake.Info(1); info.Rect != (Rect{Right: 1920, Bottom: 1080}) {
+		t.Fatalf("Expected the game to be moved once it was restored, got %+v", info.Rect)
+	}
+
+	if err := fake.SetRect(1, Rect{Right: 800, Bottom: 600}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyForeground(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := fake.Info(1); info.Rect != (Rect{Right: 800, Bottom: 600}) {
+		t.Fatalf("Expected the game not to be moved a second time, got %+v", info.Rect)
+	}
+}
@@ -0,0 +1,45 @@
+package window
+
+import (
+	"testing"
+)
+
+// useBackend replaces the package backend for the duration of the test.
+func useBackend(t testing.TB, b Backend) {
+	t.Helper()
+	previous := backend
+	backend = b
+	t.Cleanup(func() { backend = previous })
+}
+
+func TestGetWindowByProcessID(t *testing.T) {
+	useBackend(t, NewFake(
+		Info{Handle: 1, PID: 10, Title: "Other", Visible: true},
+		Info{Handle: 2, PID: 20, Title: "Hidden", Visible: false},
+		Info{Handle: 3, PID: 20, Title: "Tooltip", Visible: true, ExStyle: WS_EX_TOOLWINDOW},
+		Info{Handle: 4, PID: 20, Title: "", Visible: true},
+		Info{Handle: 5, PID: 20, Title: "Game", Visible: true},
+	))
+
+	if h := GetWindowByProcessID(20); h != 5 {
+		t.Fatalf("Expected window 5, got %d", h)
+	}
+	if h := GetWindowByProcessID(30); h != 0 {
+		t.Fatalf("Expected no window, got %d", h)
+	}
+}
+
+func TestRect(t *testing.T) {
+	monitor := Rect{Left: 0, Top: 0, Right: 2560, Bottom: 1440}
+	window := Rect{Left: 320, Top: 0, Right: 2240, Bottom: 1080}
+
+	if window.Width() != 1920 || window.Height() != 1080 || window.Area() != 1920*1080 {
+		t.Fatalf("Unexpected size of %+v", window)
+	}
+	if !monitor.Contains(window) || window.Contains(monitor) {
+		t.Fatal("Expected the monitor to contain the window and not the other way around")
+	}
+	if !(Rect{Left: 10, Right: 5, Bottom: 10}).Empty() || (Rect{Left: 10, Right: 5, Bottom: 10}).Area() != 0 {
+		t.Fatal("Expected an inverted rect to be empty")
+	}
+}
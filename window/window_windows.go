@@ -0,0 +1,225 @@
+package window
+
+import (
+	"context"
+	"log"
+	"time"
+	"unsafe"
+
+	"github.com/lxn/win"
+	"github.com/skryvvara/focusframe/process"
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32 = windows.NewLazySystemDLL("user32.dll")
+
+	procFindWindow               = user32.NewProc("FindWindowW")
+	procIsWindowVisible          = user32.NewProc("IsWindowVisible")
+	procEnumWindows              = user32.NewProc("EnumWindows")
+	procSetWindowPos             = user32.NewProc("SetWindowPos")
+	procGetWindowTextW           = user32.NewProc("GetWindowTextW")
+	procGetWindowTextLengthW     = user32.NewProc("GetWindowTextLengthW")
+	procGetWindowThreadProcessId = user32.NewProc("GetWindowThreadProcessId")
+	procGetForegroundWindow      = user32.NewProc("GetForegroundWindow")
+)
+
+const (
+	GWL_EXSTYLE      = 0xFFFFFFFFFFFFFFEC // Offset for extended window styles
+	GWL_STYLE        = 0xFFFFFFFFFFFFFFF0 // Style for tool windows
+	SWP_NOSIZE       = 0x0001
+	SWP_NOMOVE       = 0x0002
+	SWP_NOZORDER     = 0x0004
+	SWP_NOACTIVATE   = 0x0010
+	SWP_FRAMECHANGED = 0x0020
+
+	WINEVENT_OUTOFCONTEXT       = 0x0000
+	EVENT_SYSTEM_FOREGROUND     = 0x0003
+	EVENT_SYSTEM_MOVESIZESTART  = 0x000A
+	EVENT_SYSTEM_MOVESIZEEND    = 0x000B
+	EVENT_OBJECT_DESTROY        = 0x8001
+	EVENT_OBJECT_SHOW           = 0x8002
+	EVENT_OBJECT_LOCATIONCHANGE = 0x800B
+	EVENT_OBJECT_NAMECHANGE     = 0x800C
+	OBJID_WINDOW                = 0
+	CHILDID_SELF                = 0
+)
+
+// GetWindowText retrieves the full text of the window identified by the handle
+//
+// This function uses the GetWindowTextW and GetWindowTextLengthW functions from winuser.h.
+//
+// See https://learn.microsoft.com/de-de/windows/win32/api/winuser/nf-winuser-getwindowtextw
+// and https://learn.microsoft.com/de-de/windows/win32/api/winuser/nf-winuser-getwindowtextlengthw
+func GetWindowText(hwnd uintptr) string {
+	length, _, _ := procGetWindowTextLengthW.Call(hwnd)
+	if length == 0 {
+		return ""
+	}
+
+	buf := make([]uint16, length+1)
+	procGetWindowTextW.Call(hwnd, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	return windows.UTF16ToString(buf)
+}
+
+// GetForegroundWindow gets the handle to the foreground window
+//
+// This function uses the GetForegroundWindow function from winuser.h.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-getforegroundwindow
+func GetForegroundWindow() uintptr {
+	handle, _, _ := procGetForegroundWindow.Call()
+	return handle
+}
+
+// Deprecated: This function was a PoC to print a list of currently open
+// windows and is currently unneeded and unused but kept for potential use
+// later on for the v1.0.0 Version featuring a GUI to select the applications
+// to be managed via a list.
+func printWindowList() {
+	windows, err := backend.Windows()
+	if err != nil {
+		log.Println("Error listing windows:", err)
+		return
+	}
+
+	for _, win := range windows {
+		if !isMainWindowCandidate(win) {
+			continue
+		}
+
+		executable, err := process.GetExecutableFromPID(win.PID)
+		if err != nil {
+			log.Println("Error getting executable:", err)
+			continue
+		}
+
+		log.Printf("%s Executable: %s\n", win.Title, executable)
+	}
+}
+
+// ForegroundWindowEvent is called when the foreground window changes. It only hands the event to the scheduler,
+// the window is inspected and moved by applyForeground on a worker.
+func ForegroundWindowEvent(hWinEventHook win.HWINEVENTHOOK, event uint32, hwnd win.HWND, idObject int32, idChild int32, idEventThread uint32, dwmsEventTime uint32) uintptr {
+	h := Handle(hwnd)
+	activations.record(h, time.Now())
+
+	scheduler.FocusChanged(h)
+	scheduler.Schedule(Job{
+		Handle:       h,
+		Name:         "focus",
+		CancelOnBlur: true,
+		Run: func(ctx context.Context) error {
+			return applyForeground(ctx, h)
+		},
+	})
+
+	return 0
+}
+
+// objectEventKinds maps the object events FocusFrame subscribes to to the kind of Event.
+var objectEventKinds = map[uint32]EventKind{
+	EVENT_OBJECT_SHOW:           EventShown,
+	EVENT_OBJECT_DESTROY:        EventDestroyed,
+	EVENT_OBJECT_NAMECHANGE:     EventNameChanged,
+	EVENT_OBJECT_LOCATIONCHANGE: EventLocationChanged,
+}
+
+// winEventProc receives all window events FocusFrame subscribes to and dispatches them.
+func winEventProc(hWinEventHook win.HWINEVENTHOOK, event uint32, hwnd win.HWND, idObject int32, idChild int32, idEventThread uint32, dwmsEventTime uint32) uintptr {
+	switch event {
+	case EVENT_SYSTEM_FOREGROUND:
+		return ForegroundWindowEvent(hWinEventHook, event, hwnd, idObject, idChild, idEventThread, dwmsEventTime)
+	case EVENT_SYSTEM_MOVESIZESTART:
+		enforcement.dragStarted(Handle(hwnd))
+	case EVENT_SYSTEM_MOVESIZEEND:
+		enforcement.dragEnded(Handle(hwnd), time.Now())
+	case EVENT_OBJECT_SHOW, EVENT_OBJECT_DESTROY, EVENT_OBJECT_NAMECHANGE, EVENT_OBJECT_LOCATIONCHANGE:
+		// only events of windows themselves are relevant, not of their child objects like the caret or scrollbars
+		if idObject != OBJID_WINDOW || idChild != CHILDID_SELF {
+			return 0
+		}
+		// every moving window causes location changes, only enforced windows are interested in them
+		if event == EVENT_OBJECT_LOCATIONCHANGE && !enforcement.watching(Handle(hwnd)) {
+			return 0
+		}
+
+		ev := Event{Kind: objectEventKinds[event], Handle: Handle(hwnd), Time: time.Now()}
+		// the window may take focus before the job runs
+		if ev.Kind == EventShown {
+			guard.windowShown(ev.Handle, ev.Time)
+		}
+		tracking.Schedule(Job{
+			Handle: ev.Handle,
+			Name:   ev.Kind.String(),
+			Run: func(ctx context.Context) error {
+				syncWindow(ev)
+				return nil
+			},
+		})
+	}
+	return 0
+}
+
+// CreateWinEventHook sets up the SetWinEventHook for the given range of window events
+func CreateWinEventHook(eventMin uint32, eventMax uint32) win.HWINEVENTHOOK {
+	cb := win.WINEVENTPROC(winEventProc)
+
+	hook, err := win.SetWinEventHook(
+		eventMin,
+		eventMax,
+		0,
+		cb,
+		0,
+		0,
+		WINEVENT_OUTOFCONTEXT,
+	)
+	if err != nil {
+		log.Println("Error set window event hook:", err)
+		return 0
+	}
+	if hook == 0 {
+		log.Println("Failed to set hook.")
+	}
+	return hook
+}
+
+// WatchForegroundWindowChange fills the window index and starts listening for foreground window change events as well
+// as windows being shown, renamed and destroyed
+func WatchForegroundWindowChange() {
+	log.Println("Starting to watch foreground window changes")
+	hook := CreateWinEventHook(EVENT_SYSTEM_FOREGROUND, EVENT_SYSTEM_FOREGROUND)
+	if hook == 0 {
+		log.Println("Failed to create foreground window hook.")
+		return
+	}
+	defer win.UnhookWinEvent(hook)
+
+	objectHooks := []win.HWINEVENTHOOK{
+		CreateWinEventHook(EVENT_OBJECT_DESTROY, EVENT_OBJECT_SHOW),
+		CreateWinEventHook(EVENT_OBJECT_LOCATIONCHANGE, EVENT_OBJECT_NAMECHANGE),
+		CreateWinEventHook(EVENT_SYSTEM_MOVESIZESTART, EVENT_SYSTEM_MOVESIZEEND),
+	}
+	for _, objectHook := range objectHooks {
+		if objectHook == 0 {
+			log.Println("Failed to create window lifecycle hook, the window index might be outdated.")
+			continue
+		}
+		defer win.UnhookWinEvent(objectHook)
+	}
+
+	windows, err := backend.Windows()
+	if err != nil {
+		log.Println("Error listing windows:", err)
+	}
+	index.Fill(windows)
+	triggers.markSeen(windows)
+	applyRunning()
+
+	// Run a basic Windows message loop to keep the program listening
+	var msg win.MSG
+	for win.GetMessage(&msg, 0, 0, 0) > 0 {
+		win.TranslateMessage(&msg)
+		win.DispatchMessage(&msg)
+	}
+}
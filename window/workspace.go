@@ -0,0 +1,241 @@
+package window
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skryvvara/focusframe/config"
+	"github.com/skryvvara/focusframe/process"
+)
+
+var (
+	// workspaceTimeout limits how long activating a workspace may take, including waiting for the windows of launched
+	// apps and for every window to be ready. It is shorter than the 30 second timeout of IPC requests, so the CLI gets
+	// the result of activating the workspace.
+	workspaceTimeout = 25 * time.Second
+	// workspacePollInterval is the pause between two checks for the main window of a launched app.
+	workspacePollInterval = 500 * time.Millisecond
+	// launchProcess starts the executable at the given path, replaced in tests.
+	launchProcess = process.Launch
+)
+
+// OnWorkspaceChanged is called with the name of the active workspace whenever a workspace is activated or deactivated,
+// the name is empty if none is active. It must not block.
+var OnWorkspaceChanged func(name string)
+
+// MissingWindowsError is returned when activating a workspace if some of its apps had no window to arrange. The other
+// apps are arranged nevertheless.
+type MissingWindowsError struct {
+	Workspace   string
+	Executables []string
+}
+
+func (e *MissingWindowsError) Error() string {
+	return fmt.Sprintf("workspace %s is missing windows of %s", e.Workspace, strings.Join(e.Executables, ", "))
+}
+
+// workspaces is the state of the active workspace. While a workspace is active, the settings of its apps replace the
+// active layouts of the apps, so their windows stay arranged when they are applied again, e.g. on focus.
+var workspaces = struct {
+	sync.Mutex
+	active string
+	// settings are the window settings of the apps of the active workspace by executable
+	settings map[string]config.WindowSettings
+	// previous is the state of the arranged windows before the workspace was activated
+	previous []OriginalState
+}{}
+
+// appSettings returns the settings applied to the windows of the executable, which are those of the active
+// workspace if the app is part of it, otherwise those of the active layout of the app.
+func appSettings(executable string) config.WindowSettings {
+	workspaces.Lock()
+	ws, ok := workspaces.settings[executable]
+	workspaces.Unlock()
+	if ok {
+		return ws
+	}
+	return config.GetWindowSettings(executable)
+}
+
+// ActiveWorkspace returns the name of the active workspace or "" if none is active.
+func ActiveWorkspace() string {
+	workspaces.Lock()
+	defer workspaces.Unlock()
+	return workspaces.active
+}
+
+// workspaceSettings returns the window settings of every app of the workspace, with the layouts moved to the
+// configured monitors.
+//
+// Returns an error if an app is not managed, has no such layout or the monitor doesn't exist.
+func workspaceSettings(workspace config.Workspace) (map[string]config.WindowSettings, error) {
+	var monitors []Monitor
+	settings := make(map[string]config.WindowSettings, len(workspace.Apps))
+	for _, app := range workspace.Apps {
+		ws, err := config.GetLayoutSettings(app.Executable, app.Layout)
+		if err != nil {
+			return nil, err
+		}
+
+		if app.Monitor > 0 {
+			if monitors == nil {
+				all, err := backend.Monitors()
+				if err != nil {
+					return nil, err
+				}
+				monitors = sortedMonitors(all)
+			}
+			if app.Monitor > len(monitors) {
+				return nil, fmt.Errorf("monitor %d of %s doesn't exist, there are %d monitors", app.Monitor, app.Executable, len(monitors))
+			}
+			// the offsets of a layout are relative to the primary monitor, which starts at 0,0
+			origin := monitors[app.Monitor-1].Rect
+			ws.OffsetX += origin.Left
+			ws.OffsetY += origin.Top
+		}
+		settings[app.Executable] = ws
+	}
+	return settings, nil
+}
+
+// ActivateWorkspace arranges the windows of all apps of the workspace with the given name. Apps that are not running
+// are started if the workspace launches them, all at once so they share the time to show their windows. The state of
+// the windows before is kept, DeactivateWorkspace restores it. Another active workspace is deactivated first.
+//
+// Returns an error if the workspace doesn't exist or is invalid, window management is paused, or a
+// *MissingWindowsError if some of its apps had no window to arrange.
+func ActivateWorkspace(ctx context.Context, name string) error {
+	workspace, ok := config.GetWorkspace(name)
+	if !ok {
+		return fmt.Errorf("workspace %s doesn't exist", name)
+	}
+	if pausing.paused() {
+		return ErrPaused
+	}
+	settings, err := workspaceSettings(workspace)
+	if err != nil {
+		return fmt.Errorf("workspace %s: %w", name, err)
+	}
+
+	if ActiveWorkspace() != "" {
+		DeactivateWorkspace()
+	}
+
+	workspaces.Lock()
+	workspaces.active = name
+	workspaces.settings = settings
+	workspaces.previous = nil
+	workspaces.Unlock()
+	log.Printf("Activated workspace %s\n", name)
+	workspaceChanged(name)
+
+	ctx, cancel := context.WithTimeout(ctx, workspaceTimeout)
+	defer cancel()
+
+	handles := make([]Handle, len(workspace.Apps))
+	errs := make([]error, len(workspace.Apps))
+	var wg sync.WaitGroup
+	for i, app := range workspace.Apps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handles[i], errs[i] = workspaceWindow(ctx, app.Executable, workspace.Launch)
+		}()
+	}
+	wg.Wait()
+
+	var missing []string
+	for i, app := range workspace.Apps {
+		h := handles[i]
+		if err := errs[i]; err != nil {
+			log.Printf("No window of %s to arrange for workspace %s: %v\n", app.Executable, name, err)
+			missing = append(missing, app.Executable)
+			continue
+		}
+
+		if info, err := backend.Info(h); err == nil {
+			workspaces.Lock()
+			workspaces.previous = append(workspaces.previous, originalState(info, app.Executable))
+			workspaces.Unlock()
+		}
+		if err := applyToWindow(ctx, h, app.Executable); err != nil {
+			log.Printf("Error arranging %s for workspace %s: %v\n", app.Executable, name, err)
+		}
+	}
+
+	if len(missing) > 0 {
+		return &MissingWindowsError{Workspace: name, Executables: missing}
+	}
+	return nil
+}
+
+// workspaceWindow returns the main window of the app, launching it and waiting for its window first if it is not
+// running and launch is set. The wait ends with the context.
+func workspaceWindow(ctx context.Context, executable string, launch bool) (Handle, error) {
+	pid, err := processIDOfExecutable(executable)
+	if err == nil {
+		if h := mainWindowOf(pid); h != 0 {
+			mainWindows.track(pid, executable, h)
+			return h, nil
+		}
+		return 0, fmt.Errorf("%w for process %d of %s", ErrWindowNotFound, pid, executable)
+	}
+	if !launch || !errors.Is(err, process.ErrProcessNotFound) {
+		return 0, err
+	}
+
+	app, _ := config.GetManagedApp(executable)
+	path := app.Launch
+	if path == "" {
+		return 0, fmt.Errorf("%w, it has no launch setting to start it with", err)
+	}
+	if err := launchProcess(path); err != nil {
+		return 0, err
+	}
+
+	for {
+		select {
+		case <-time.After(workspacePollInterval):
+		case <-ctx.Done():
+			return 0, fmt.Errorf("%w after launching %s: %v", ErrWindowNotFound, executable, ctx.Err())
+		}
+		// launchers start the app as another process, so it is looked up by its executable
+		if pid, err := processIDOfExecutable(executable); err == nil {
+			if h := mainWindowOf(pid); h != 0 {
+				mainWindows.track(pid, executable, h)
+				return h, nil
+			}
+		}
+	}
+}
+
+// DeactivateWorkspace puts the windows arranged by the active workspace back into the state they had before and lets
+// the apps use their own layouts again.
+func DeactivateWorkspace() {
+	workspaces.Lock()
+	name, previous := workspaces.active, workspaces.previous
+	workspaces.active, workspaces.settings, workspaces.previous = "", nil, nil
+	workspaces.Unlock()
+	if name == "" {
+		return
+	}
+
+	for _, state := range previous {
+		if err := restoreState(state); err != nil {
+			log.Printf("Error restoring window %#x of %s: %v\n", state.Handle, state.Executable, err)
+		}
+	}
+	log.Printf("Deactivated workspace %s\n", name)
+	workspaceChanged("")
+}
+
+func workspaceChanged(name string) {
+	if OnWorkspaceChanged != nil {
+		OnWorkspaceChanged(name)
+	}
+}
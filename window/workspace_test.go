@@ -0,0 +1,165 @@
+package window
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// useWorkspaces replaces the workspaces of the config for the duration of the test and deactivates the active one
+// afterwards without restoring its windows.
+func useWorkspaces(t *testing.T, workspaces map[string]config.Workspace) {
+	t.Helper()
+	previous := config.Config.Workspaces
+	config.Config.Workspaces = workspaces
+	t.Cleanup(func() {
+		config.Config.Workspaces = previous
+		resetWorkspaces()
+	})
+}
+
+func resetWorkspaces() {
+	workspaces.Lock()
+	workspaces.active, workspaces.settings, workspaces.previous = "", nil, nil
+	workspaces.Unlock()
+}
+
+// streaming sets up a game running on the left monitor and a chat app that is not running, with a workspace placing
+// the wide layout of the game on the right monitor next to the chat.
+func streaming(t *testing.T, launch bool) (*Fake, *executables) {
+	t.Helper()
+	gameApp := game(config.StateSettings{})
+	gameApp.Layouts = []config.Layout{{Name: "wide", Dimensions: config.WindowSettings{Width: 1280, Height: 540}}}
+	chat := game(config.StateSettings{})
+	chat.Executable = "Chat.exe"
+	chat.Launch = `C:\Chat\Chat.exe`
+	chat.Dimensions = config.WindowSettings{Width: 640, Height: 1040}
+	useApps(t, gameApp, chat)
+
+	fake := NewFake(Info{Handle: 1, PID: 10, Title: "Game", Visible: true, Rect: Rect{Left: 100, Top: 100, Right: 900, Bottom: 700}, Monitor: left})
+	fake.SetMonitors(left, right)
+	useBackend(t, fake)
+	exes := &executables{names: map[uint32]string{10: "Game.exe"}}
+	useIndex(t, NewIndex(exes.resolve))
+	index.Add(Info{Handle: 1, PID: 10})
+	useMainWindows(t)
+	useJournal(t, openJournal(t, ""))
+
+	useWorkspaces(t, map[string]config.Workspace{
+		"streaming": {
+			Launch: launch,
+			Apps: []config.WorkspaceApp{
+				{Executable: "Game.exe", Layout: "wide", Monitor: 2},
+				{Executable: "Chat.exe"},
+			},
+		},
+	})
+	return fake, exes
+}
+
+func TestActivateWorkspace(t *testing.T) {
+	fake, _ := streaming(t, false)
+	changes := recordWorkspaces(t)
+
+	err := ActivateWorkspace(context.Background(), "streaming")
+	var missing *MissingWindowsError
+	if !errors.As(err, &missing) || len(missing.Executables) != 1 || missing.Executables[0] != "Chat.exe" {
+		t.Fatalf("Expected the window of Chat.exe to be missing, got %v", err)
+	}
+	if info, _ := fake.Info(1); info.Rect != (Rect{Left: 1920, Right: 3200, Bottom: 540}) {
+		t.Fatalf("Expected the wide layout on the right monitor, got %+v", info.Rect)
+	}
+	if ws := appSettings("Game.exe"); ws.OffsetX != 1920 {
+		t.Fatalf("Expected the workspace to replace the settings of the game, got %+v", ws)
+	}
+
+	DeactivateWorkspace()
+	if info, _ := fake.Info(1); info.Rect != (Rect{Left: 100, Top: 100, Right: 900, Bottom: 700}) {
+		t.Fatalf("Expected the previous arrangement to be restored, got %+v", info.Rect)
+	}
+	if ws := appSettings("Game.exe"); ws.Width != 1920 || ActiveWorkspace() != "" {
+		t.Fatalf("Expected the game to use its own layout again, got %+v", ws)
+	}
+	if len(*changes) != 2 || (*changes)[0] != "streaming" || (*changes)[1] != "" {
+		t.Fatalf("Unexpected workspace changes %v", *changes)
+	}
+}
+
+func TestActivateWorkspaceLaunches(t *testing.T) {
+	fake, exes := streaming(t, true)
+	previous := workspacePollInterval
+	workspacePollInterval = time.Millisecond
+	t.Cleanup(func() { workspacePollInterval = previous })
+
+	var launched []string
+	previousLaunch := launchProcess
+	launchProcess = func(path string) error {
+		launched = append(launched, path)
+		exes.names[20] = "Chat.exe"
+		fake.Add(Info{Handle: 2, PID: 20, Title: "Chat", Visible: true, Rect: Rect{Right: 400, Bottom: 400}})
+		index.Add(Info{Handle: 2, PID: 20})
+		return nil
+	}
+	t.Cleanup(func() { launchProcess = previousLaunch })
+
+	if err := ActivateWorkspace(context.Background(), "streaming"); err != nil {
+		t.Fatal(err)
+	}
+	if len(launched) != 1 || launched[0] != `C:\Chat\Chat.exe` {
+		t.Fatalf("Expected the chat to be launched, got %v", launched)
+	}
+	if info, _ := fake.Info(2); info.Rect != (Rect{Right: 640, Bottom: 1040}) {
+		t.Fatalf("Expected the launched chat to be arranged, got %+v", info.Rect)
+	}
+}
+
+func TestActivateWorkspaceTimeout(t *testing.T) {
+	streaming(t, true)
+	previous, previousTimeout, previousLaunch := workspacePollInterval, workspaceTimeout, launchProcess
+	workspacePollInterval, workspaceTimeout = time.Millisecond, 50*time.Millisecond
+	// the chat never shows a window
+	launchProcess = func(path string) error { return nil }
+	t.Cleanup(func() {
+		workspacePollInterval, workspaceTimeout, launchProcess = previous, previousTimeout, previousLaunch
+	})
+
+	start := time.Now()
+	err := ActivateWorkspace(context.Background(), "streaming")
+	var missing *MissingWindowsError
+	if !errors.As(err, &missing) || len(missing.Executables) != 1 || missing.Executables[0] != "Chat.exe" {
+		t.Fatalf("Expected the window of Chat.exe to be missing, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Expected activating the workspace to give up after its timeout, took %v", elapsed)
+	}
+}
+
+func TestActivateWorkspaceErrors(t *testing.T) {
+	streaming(t, false)
+	usePausing(t)
+
+	if err := ActivateWorkspace(context.Background(), "missing"); err == nil {
+		t.Fatal("Expected an error for a missing workspace")
+	}
+
+	config.Config.Workspaces["third"] = config.Workspace{Apps: []config.WorkspaceApp{{Executable: "Game.exe", Monitor: 3}}}
+	if err := ActivateWorkspace(context.Background(), "third"); err == nil || ActiveWorkspace() != "" {
+		t.Fatalf("Expected an error for a missing monitor, got %v", err)
+	}
+
+	Pause(0)
+	if err := ActivateWorkspace(context.Background(), "streaming"); !errors.Is(err, ErrPaused) {
+		t.Fatalf("Expected ErrPaused, got %v", err)
+	}
+}
+
+// recordWorkspaces records every change of the active workspace for the duration of the test.
+func recordWorkspaces(t *testing.T) *[]string {
+	var changes []string
+	OnWorkspaceChanged = func(name string) { changes = append(changes, name) }
+	t.Cleanup(func() { OnWorkspaceChanged = nil })
+	return &changes
+}
@@ -0,0 +1,109 @@
+package window
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/skryvvara/focusframe/config"
+	"github.com/skryvvara/focusframe/zones"
+)
+
+// focusedZones returns the focused window, its state and the zones of the monitor it is on. The window is not changed,
+// see restoreForZone.
+func focusedZones() (Handle, Info, []zones.Rect, error) {
+	h := backend.Foreground()
+	if h == 0 {
+		return 0, Info{}, nil, fmt.Errorf("%w: no window has focus", ErrWindowNotFound)
+	}
+	info, err := backend.Info(h)
+	if err != nil {
+		return 0, Info{}, nil, err
+	}
+	monitors, err := backend.Monitors()
+	if err != nil {
+		return 0, Info{}, nil, err
+	}
+
+	number := slices.IndexFunc(sortedMonitors(monitors), func(m Monitor) bool { return m.Rect == info.Monitor.Rect }) + 1
+	layout, ok := config.GetZoneLayout(number)
+	if !ok {
+		return 0, Info{}, nil, fmt.Errorf("monitor %d has no zone layout", number)
+	}
+	area := info.Monitor.WorkArea
+	if area.Empty() {
+		area = info.Monitor.Rect
+	}
+	resolved, err := layout.Layout().Resolve(zones.Rect(area))
+	if err != nil {
+		return 0, Info{}, nil, fmt.Errorf("invalid zone layout of monitor %d: %w", number, err)
+	}
+	return h, info, resolved, nil
+}
+
+// restoreForZone restores a maximized window, so it can be moved into a zone, and returns its new state.
+func restoreForZone(h Handle, info Info) (Info, error) {
+	if !info.Maximized {
+		return info, nil
+	}
+	if err := backend.SetShowState(h, ShowNormal); err != nil {
+		return Info{}, err
+	}
+	return backend.Info(h)
+}
+
+// moveToZone moves the visible frame of the window to the rect of a zone.
+func moveToZone(h Handle, zone zones.Rect) error {
+	// moving a window into a zone is what the user wants now, the enforced geometry would move it back
+	enforcement.forget(h)
+
+	r := Rect(zone)
+	return SetGeometry(context.Background(), h, config.WindowSettings{
+		Width:    r.Width(),
+		Height:   r.Height(),
+		OffsetX:  r.Left,
+		OffsetY:  r.Top,
+		SizeMode: config.SizeModeFrame,
+	})
+}
+
+// SendToZone moves the focused window into the zone with the given number of the zone layout of its monitor.
+//
+// Returns an error if no window has focus, its monitor has no valid zone layout, the zone doesn't exist or the window
+// could not be moved.
+func SendToZone(zone int) error {
+	return SpanZones(zone, zone)
+}
+
+// NextZone moves the focused window into the zone following the one it is in, see SendToZone.
+func NextZone() error {
+	h, info, resolved, err := focusedZones()
+	if err != nil {
+		return err
+	}
+	if info, err = restoreForZone(h, info); err != nil {
+		return err
+	}
+	next := zones.Next(resolved, zones.Rect(measuredRect(info, config.SizeModeFrame)))
+	log.Printf("Moving window %#x to zone %d\n", h, next)
+	return moveToZone(h, resolved[next-1])
+}
+
+// SpanZones moves the focused window over the zones from first to last, so it covers all of them, see SendToZone.
+func SpanZones(first int, last int) error {
+	h, info, resolved, err := focusedZones()
+	if err != nil {
+		return err
+	}
+	// validate the zones before restoring the window, a missing zone should leave it maximized
+	span, err := zones.Span(resolved, first, last)
+	if err != nil {
+		return err
+	}
+	if _, err := restoreForZone(h, info); err != nil {
+		return err
+	}
+	log.Printf("Moving window %#x to zones %d-%d\n", h, first, last)
+	return moveToZone(h, span)
+}
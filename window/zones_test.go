@@ -0,0 +1,64 @@
+package window
+
+import (
+	"testing"
+
+	"github.com/skryvvara/focusframe/config"
+)
+
+// useZones replaces the zone layouts of the config for the duration of the test.
+func useZones(t *testing.T, layouts ...config.ZoneLayout) {
+	t.Helper()
+	previous := config.Config.Zones
+	config.Config.Zones = layouts
+	t.Cleanup(func() { config.Config.Zones = previous })
+}
+
+func TestZones(t *testing.T) {
+	fake := NewFake(Info{Handle: 1, Visible: true, Maximized: true, Rect: left.Rect, Monitor: left})
+	fake.SetMonitors(left, right)
+	fake.SetForeground(1)
+	useBackend(t, fake)
+	useZones(t, config.ZoneLayout{Monitor: 1, Type: "columns", Columns: 3})
+
+	if err := SendToZone(4); err == nil {
+		t.Fatal("Expected an error for a missing zone")
+	}
+	if info, _ := fake.Info(1); !info.Maximized {
+		t.Fatal("Expected the window to stay maximized when the zone doesn't exist")
+	}
+
+	if err := SendToZone(2); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := fake.Info(1)
+	if info.Maximized || info.Rect != (Rect{Left: 640, Right: 1280, Bottom: 1040}) {
+		t.Fatalf("Expected the window to be restored and moved to zone 2, got %+v", info)
+	}
+
+	if err := NextZone(); err != nil {
+		t.Fatal(err)
+	}
+	if err := NextZone(); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := fake.Info(1); info.Rect != (Rect{Right: 640, Bottom: 1040}) {
+		t.Fatalf("Expected the window to wrap around to zone 1, got %+v", info.Rect)
+	}
+
+	if err := SpanZones(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := fake.Info(1); info.Rect != (Rect{Right: 1280, Bottom: 1040}) {
+		t.Fatalf("Expected the window to span zones 1-2, got %+v", info.Rect)
+	}
+	if err := SendToZone(4); err == nil {
+		t.Fatal("Expected an error for a missing zone")
+	}
+
+	// the right monitor has no layout of its own and none for all monitors
+	fake.Update(1, func(info *Info) { info.Monitor = right })
+	if err := SendToZone(1); err == nil {
+		t.Fatal("Expected an error for a monitor without zones")
+	}
+}
@@ -0,0 +1,338 @@
+// Package zones divides an area of the screen into zones windows can be placed in, like the layouts of FancyZones.
+// It only does the maths, moving windows is up to the caller.
+package zones
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rect is an area on the screen in pixels.
+type Rect struct {
+	Left, Top, Right, Bottom int
+}
+
+// Width returns the width of the rect.
+func (r Rect) Width() int {
+	return r.Right - r.Left
+}
+
+// Height returns the height of the rect.
+func (r Rect) Height() int {
+	return r.Bottom - r.Top
+}
+
+// Union returns the smallest rect containing both rects.
+func (r Rect) Union(o Rect) Rect {
+	return Rect{Left: min(r.Left, o.Left), Top: min(r.Top, o.Top), Right: max(r.Right, o.Right), Bottom: max(r.Bottom, o.Bottom)}
+}
+
+// overlap returns the area both rects cover.
+func (r Rect) overlap(o Rect) int {
+	width := min(r.Right, o.Right) - max(r.Left, o.Left)
+	height := min(r.Bottom, o.Bottom) - max(r.Top, o.Top)
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+	return width * height
+}
+
+// Kind is the way a Layout divides an area.
+type Kind string
+
+const (
+	// Grid divides the area into rows and columns, cells can be merged into a single zone.
+	Grid Kind = "grid"
+	// Columns divides the area into columns of the full height.
+	Columns Kind = "columns"
+	// Rows divides the area into rows of the full width.
+	Rows Kind = "rows"
+	// Centered has a large zone in the middle with a side panel to the left and the right of it.
+	Centered Kind = "centered"
+	// Canvas has freely placed zones, which may overlap.
+	Canvas Kind = "canvas"
+)
+
+// DefaultCenter is the width of the middle zone of a Centered layout in percent of the area.
+const DefaultCenter = 60
+
+// Layout describes how an area is divided into zones. Zones are numbered from 1, for grids in the order of their
+// first cell from left to right and top to bottom unless Cells says otherwise.
+type Layout struct {
+	Kind Kind
+	// Rows and Columns are the relative heights of the rows and widths of the columns, e.g. [1, 2, 1]. Without
+	// weights, RowCount and ColumnCount rows and columns of equal size are used.
+	Rows, Columns []int
+	// RowCount and ColumnCount are the number of rows and columns of equal size if no weights are given.
+	RowCount, ColumnCount int
+	// Cells assigns every cell of a grid the number of its zone, starting at 1. Neighbouring cells with the same
+	// number are merged, the cells of a zone have to form a rectangle. Each cell is its own zone if empty.
+	Cells [][]int
+	// Center is the width of the middle zone of a Centered layout in percent, DefaultCenter if 0.
+	Center int
+	// Zones are the zones of a Canvas, relative to an area of ReferenceWidth x ReferenceHeight. They are scaled to the
+	// actual area.
+	Zones                           []Rect
+	ReferenceWidth, ReferenceHeight int
+	// Spacing is the gap between the zones and around them in pixels.
+	Spacing int
+}
+
+// Resolve returns the zones of the layout in the given area, ordered by their number.
+//
+// Returns an error if the layout is invalid, e.g. it has no zones or the cells of a zone don't form a rectangle.
+func (l Layout) Resolve(area Rect) ([]Rect, error) {
+	if l.Spacing < 0 {
+		return nil, errors.New("spacing must not be negative")
+	}
+
+	switch l.Kind {
+	case Grid, Columns, Rows, Centered:
+		rows, columns, cells, err := l.grid()
+		if err != nil {
+			return nil, err
+		}
+		return l.resolveGrid(area, rows, columns, cells)
+	case Canvas:
+		return l.resolveCanvas(area)
+	default:
+		return nil, fmt.Errorf("unknown zone layout %q, expected %s, %s, %s, %s or %s", l.Kind, Grid, Columns, Rows, Centered, Canvas)
+	}
+}
+
+// grid returns the row and column weights and the cell map of the layouts that are grids.
+func (l Layout) grid() ([]int, []int, [][]int, error) {
+	switch l.Kind {
+	case Columns:
+		columns, err := weights(l.Columns, l.ColumnCount, "columns")
+		return []int{1}, columns, nil, err
+	case Rows:
+		rows, err := weights(l.Rows, l.RowCount, "rows")
+		return rows, []int{1}, nil, err
+	case Centered:
+		center := l.Center
+		if center == 0 {
+			center = DefaultCenter
+		}
+		if center <= 0 || center >= 100 {
+			return nil, nil, nil, fmt.Errorf("center must be between 0 and 100 percent, got %d", center)
+		}
+		side := 100 - center
+		return []int{1}, []int{side, 2 * center, side}, nil, nil
+	}
+
+	rows, err := weights(l.Rows, l.RowCount, "rows")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	columns, err := weights(l.Columns, l.ColumnCount, "columns")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return rows, columns, l.Cells, nil
+}
+
+// weights returns the given weights or count equal ones.
+func weights(given []int, count int, name string) ([]int, error) {
+	if len(given) == 0 {
+		if count <= 0 {
+			return nil, fmt.Errorf("the number of %s must be at least 1", name)
+		}
+		given = make([]int, count)
+		for i := range given {
+			given[i] = 1
+		}
+	}
+	for _, w := range given {
+		if w <= 0 {
+			return nil, fmt.Errorf("the sizes of the %s must be positive, got %v", name, given)
+		}
+	}
+	return given, nil
+}
+
+// split returns the boundaries of dividing the range from start to end into parts of the given weights.
+func split(start int, end int, weights []int) []int {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	bounds := []int{start}
+	sum := 0
+	for _, w := range weights {
+		sum += w
+		bounds = append(bounds, start+(end-start)*sum/total)
+	}
+	return bounds
+}
+
+func (l Layout) resolveGrid(area Rect, rows []int, columns []int, cells [][]int) ([]Rect, error) {
+	if cells == nil {
+		cells = make([][]int, len(rows))
+		for r := range cells {
+			cells[r] = make([]int, len(columns))
+			for c := range cells[r] {
+				cells[r][c] = r*len(columns) + c + 1
+			}
+		}
+	}
+	if len(cells) != len(rows) {
+		return nil, fmt.Errorf("cells has %d rows, expected %d", len(cells), len(rows))
+	}
+
+	ys := split(area.Top, area.Bottom, rows)
+	xs := split(area.Left, area.Right, columns)
+
+	count := 0
+	for r, row := range cells {
+		if len(row) != len(columns) {
+			return nil, fmt.Errorf("row %d of cells has %d columns, expected %d", r+1, len(row), len(columns))
+		}
+		for _, zone := range row {
+			if zone <= 0 {
+				return nil, fmt.Errorf("zones are numbered from 1, got %d", zone)
+			}
+			count = max(count, zone)
+		}
+	}
+
+	zones := make([]Rect, count)
+	found := make([]bool, count)
+	cellCount := make([]int, count)
+	for r, row := range cells {
+		for c, zone := range row {
+			cell := Rect{Left: xs[c], Top: ys[r], Right: xs[c+1], Bottom: ys[r+1]}
+			if !found[zone-1] {
+				zones[zone-1] = cell
+				found[zone-1] = true
+			} else {
+				zones[zone-1] = zones[zone-1].Union(cell)
+			}
+			cellCount[zone-1]++
+		}
+	}
+
+	for i, zone := range zones {
+		if !found[i] {
+			return nil, fmt.Errorf("zone %d has no cells", i+1)
+		}
+		if cellsIn(zone, xs, ys) != cellCount[i] {
+			return nil, fmt.Errorf("the cells of zone %d don't form a rectangle", i+1)
+		}
+		zones[i] = l.space(zone, area)
+	}
+	return zones, nil
+}
+
+// cellsIn returns the number of grid cells the rect covers.
+func cellsIn(r Rect, xs []int, ys []int) int {
+	columns, rows := 0, 0
+	for i := 0; i+1 < len(xs); i++ {
+		if xs[i] >= r.Left && xs[i+1] <= r.Right {
+			columns++
+		}
+	}
+	for i := 0; i+1 < len(ys); i++ {
+		if ys[i] >= r.Top && ys[i+1] <= r.Bottom {
+			rows++
+		}
+	}
+	return columns * rows
+}
+
+// space shrinks the zone by the spacing, so neighbouring zones are spacing apart and zones at the edge of the area
+// are spacing away from it.
+func (l Layout) space(zone Rect, area Rect) Rect {
+	// neighbours split the gap between them
+	lower, upper := l.Spacing-l.Spacing/2, l.Spacing/2
+	inset := func(atEdge bool, half int) int {
+		if atEdge {
+			return l.Spacing
+		}
+		return half
+	}
+	return Rect{
+		Left:   zone.Left + inset(zone.Left == area.Left, lower),
+		Top:    zone.Top + inset(zone.Top == area.Top, lower),
+		Right:  zone.Right - inset(zone.Right == area.Right, upper),
+		Bottom: zone.Bottom - inset(zone.Bottom == area.Bottom, upper),
+	}
+}
+
+func (l Layout) resolveCanvas(area Rect) ([]Rect, error) {
+	if len(l.Zones) == 0 {
+		return nil, errors.New("a canvas needs at least one zone")
+	}
+	if l.ReferenceWidth <= 0 || l.ReferenceHeight <= 0 {
+		return nil, errors.New("a canvas needs the size of the area its zones are relative to")
+	}
+
+	zones := make([]Rect, len(l.Zones))
+	for i, z := range l.Zones {
+		if z.Width() <= 0 || z.Height() <= 0 {
+			return nil, fmt.Errorf("zone %d of the canvas is empty", i+1)
+		}
+		zones[i] = Rect{
+			Left:   area.Left + z.Left*area.Width()/l.ReferenceWidth,
+			Top:    area.Top + z.Top*area.Height()/l.ReferenceHeight,
+			Right:  area.Left + z.Right*area.Width()/l.ReferenceWidth,
+			Bottom: area.Top + z.Bottom*area.Height()/l.ReferenceHeight,
+		}
+	}
+	return zones, nil
+}
+
+// Best returns the number of the zone r overlaps the most, or 0 if it overlaps none.
+func Best(zones []Rect, r Rect) int {
+	best, most := 0, 0
+	for i, zone := range zones {
+		if o := zone.overlap(r); o > most {
+			best, most = i+1, o
+		}
+	}
+	return best
+}
+
+// Next returns the number of the zone following the one r is in (see Best), wrapping around after the last one. A
+// rect outside of all zones is followed by zone 1.
+func Next(zones []Rect, r Rect) int {
+	return Best(zones, r)%len(zones) + 1
+}
+
+// Span returns the smallest rect containing the zones from first to last, both counted from 1.
+//
+// Returns an error if a zone doesn't exist.
+func Span(zones []Rect, first int, last int) (Rect, error) {
+	if first > last {
+		first, last = last, first
+	}
+	if first < 1 || last > len(zones) {
+		return Rect{}, fmt.Errorf("zones %d-%d don't exist, there are %d zones", first, last, len(zones))
+	}
+	span := zones[first-1]
+	for _, zone := range zones[first:last] {
+		span = span.Union(zone)
+	}
+	return span, nil
+}
+
+// ParseRange parses a range of zones like "1-3", or a single zone like "2".
+//
+// Returns either the first and last zone or an error if the range is malformed.
+func ParseRange(s string) (int, int, error) {
+	from, to, found := strings.Cut(s, "-")
+	first, err := strconv.Atoi(strings.TrimSpace(from))
+	if err != nil || first < 1 {
+		return 0, 0, fmt.Errorf("invalid zone range %q, expected e.g. 1-2", s)
+	}
+	if !found {
+		return first, first, nil
+	}
+	last, err := strconv.Atoi(strings.TrimSpace(to))
+	if err != nil || last < 1 {
+		return 0, 0, fmt.Errorf("invalid zone range %q, expected e.g. 1-2", s)
+	}
+	return first, last, nil
+}
@@ -0,0 +1,147 @@
+package zones
+
+import (
+	"reflect"
+	"testing"
+)
+
+var area = Rect{Right: 1200, Bottom: 600}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout Layout
+		want   []Rect
+	}{
+		{"columns", Layout{Kind: Columns, ColumnCount: 3}, []Rect{
+			{Right: 400, Bottom: 600}, {Left: 400, Right: 800, Bottom: 600}, {Left: 800, Right: 1200, Bottom: 600},
+		}},
+		{"weighted rows", Layout{Kind: Rows, Rows: []int{1, 2}}, []Rect{
+			{Right: 1200, Bottom: 200}, {Top: 200, Right: 1200, Bottom: 600},
+		}},
+		{"grid", Layout{Kind: Grid, RowCount: 2, ColumnCount: 2}, []Rect{
+			{Right: 600, Bottom: 300}, {Left: 600, Right: 1200, Bottom: 300},
+			{Top: 300, Right: 600, Bottom: 600}, {Left: 600, Top: 300, Right: 1200, Bottom: 600},
+		}},
+		{"merged cells", Layout{Kind: Grid, RowCount: 2, ColumnCount: 2, Cells: [][]int{{1, 2}, {1, 3}}}, []Rect{
+			{Right: 600, Bottom: 600}, {Left: 600, Right: 1200, Bottom: 300}, {Left: 600, Top: 300, Right: 1200, Bottom: 600},
+		}},
+		{"centered", Layout{Kind: Centered, Center: 50}, []Rect{
+			{Right: 300, Bottom: 600}, {Left: 300, Right: 900, Bottom: 600}, {Left: 900, Right: 1200, Bottom: 600},
+		}},
+		{"canvas", Layout{Kind: Canvas, ReferenceWidth: 2400, ReferenceHeight: 1200, Zones: []Rect{
+			{Right: 2400, Bottom: 1200}, {Left: 1200, Top: 600, Right: 2400, Bottom: 1200},
+		}}, []Rect{
+			{Right: 1200, Bottom: 600}, {Left: 600, Top: 300, Right: 1200, Bottom: 600},
+		}},
+	}
+
+	for _, test := range tests {
+		got, err := test.layout.Resolve(area)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+}
+
+func TestResolveOffsetArea(t *testing.T) {
+	// a work area of a monitor to the right of the primary one
+	got, err := Layout{Kind: Columns, ColumnCount: 2}.Resolve(Rect{Left: 1920, Top: 40, Right: 3840, Bottom: 1080})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Rect{{Left: 1920, Top: 40, Right: 2880, Bottom: 1080}, {Left: 2880, Top: 40, Right: 3840, Bottom: 1080}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestResolveSpacing(t *testing.T) {
+	got, err := Layout{Kind: Columns, ColumnCount: 2, Spacing: 10}.Resolve(area)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Rect{{Left: 10, Top: 10, Right: 595, Bottom: 590}, {Left: 605, Top: 10, Right: 1190, Bottom: 590}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestResolveInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout Layout
+	}{
+		{"unknown kind", Layout{Kind: "spiral"}},
+		{"no columns", Layout{Kind: Columns}},
+		{"negative weight", Layout{Kind: Rows, Rows: []int{1, -1}}},
+		{"center too wide", Layout{Kind: Centered, Center: 100}},
+		{"cells not a rectangle", Layout{Kind: Grid, RowCount: 2, ColumnCount: 2, Cells: [][]int{{1, 1}, {1, 2}}}},
+		{"cells of wrong size", Layout{Kind: Grid, RowCount: 2, ColumnCount: 2, Cells: [][]int{{1, 2}}}},
+		{"missing zone", Layout{Kind: Grid, RowCount: 1, ColumnCount: 2, Cells: [][]int{{1, 3}}}},
+		{"empty canvas", Layout{Kind: Canvas, ReferenceWidth: 100, ReferenceHeight: 100}},
+		{"canvas without reference", Layout{Kind: Canvas, Zones: []Rect{{Right: 10, Bottom: 10}}}},
+		{"negative spacing", Layout{Kind: Columns, ColumnCount: 1, Spacing: -1}},
+	}
+
+	for _, test := range tests {
+		if zones, err := test.layout.Resolve(area); err == nil {
+			t.Errorf("%s: expected an error, got %v", test.name, zones)
+		}
+	}
+}
+
+func TestBestAndNext(t *testing.T) {
+	zones, _ := Layout{Kind: Columns, ColumnCount: 3}.Resolve(area)
+
+	if best := Best(zones, Rect{Left: 350, Right: 750, Bottom: 600}); best != 2 {
+		t.Fatalf("Expected the window to be mostly in zone 2, got %d", best)
+	}
+	if next := Next(zones, zones[2]); next != 1 {
+		t.Fatalf("Expected to wrap around to zone 1, got %d", next)
+	}
+	if next := Next(zones, Rect{Left: 5000, Right: 6000, Bottom: 100}); next != 1 {
+		t.Fatalf("Expected zone 1 for a window outside of all zones, got %d", next)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	zones, _ := Layout{Kind: Grid, RowCount: 2, ColumnCount: 2}.Resolve(area)
+
+	got, err := Span(zones, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != area {
+		t.Fatalf("Expected zones 2-3 to span the area, got %v", got)
+	}
+	if _, err := Span(zones, 4, 5); err == nil {
+		t.Fatal("Expected an error for a missing zone")
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		s           string
+		first, last int
+		valid       bool
+	}{
+		{"1-2", 1, 2, true},
+		{"3", 3, 3, true},
+		{" 2 - 4 ", 2, 4, true},
+		{"0-1", 0, 0, false},
+		{"1-", 0, 0, false},
+		{"a-b", 0, 0, false},
+	}
+
+	for _, test := range tests {
+		first, last, err := ParseRange(test.s)
+		if (err == nil) != test.valid || first != test.first || last != test.last {
+			t.Errorf("%q: expected %d-%d (valid %v), got %d-%d, %v", test.s, test.first, test.last, test.valid, first, last, err)
+		}
+	}
+}